@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+)
+
+// computeParams are the GET /fizzbuzz query parameters available as
+// compute flags, along with their usage.
+var computeParams = []struct {
+	name  string
+	usage string
+	bool  bool
+}{
+	{name: "int1", usage: "fizzbuzz's first multiple (default 3)"},
+	{name: "int2", usage: "fizzbuzz's second multiple (default 5)"},
+	{name: "str1", usage: "fizzbuzz's first replacement (default fizz)"},
+	{name: "str2", usage: "fizzbuzz's second replacement (default buzz)"},
+	{name: "rule", usage: "fizzbuzz's rule, as divisor:word or kind:arg:word, may be repeated"},
+	{name: "limit", usage: "fizzbuzz's up-to value (default 100)"},
+	{name: "from", usage: "fizzbuzz's starting value (default 1)"},
+	{name: "to", usage: "fizzbuzz's up-to value, replaces limit"},
+	{name: "step", usage: "fizzbuzz's increment (default 1)"},
+	{name: "combine", usage: "fizzbuzz's combination of several matching words: concat, first or override (default concat)"},
+	{name: "separator", usage: "fizzbuzz's separator of concatenated words"},
+	{name: "reverse", usage: "fizzbuzz's combination from the last matching word", bool: true},
+	{name: "override", usage: "fizzbuzz's combination override, as divisor:word, may be repeated"},
+	{name: "numbers", usage: "fizzbuzz's numbers rendering, as decimal, hex, binary, base:N, roman, padded:N or words (default decimal)"},
+	{name: "shape", usage: "output's shape, list or periodic (default list)"},
+	{name: "stream", usage: "stream terms as newline delimited JSON", bool: true},
+	{name: "page_size", usage: "paginated output's page size"},
+	{name: "cursor", usage: "paginated output's page cursor"},
+	{name: "format", usage: "output's format: json, xml, text, csv, msgpack or ndjson (default json)"},
+}
+
+// queryFlag is a flag.Value adding its values to a query parameter.
+type queryFlag struct {
+	query url.Values
+	name  string
+	bool  bool
+}
+
+func (f queryFlag) String() string {
+	return ""
+}
+
+func (f queryFlag) Set(value string) error {
+	f.query.Add(f.name, value)
+	return nil
+}
+
+func (f queryFlag) IsBoolFlag() bool {
+	return f.bool
+}
+
+// compute prints to stdout the body GET /fizzbuzz would respond to the
+// flags, returning the exit code.
+func compute(args []string) int {
+	fs := flag.NewFlagSet("compute", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: fizzbuzz-api compute [flags]")
+		fmt.Fprintln(fs.Output(), "\nPrints the fizzbuzz sequence GET /fizzbuzz would respond with.")
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+
+	query := url.Values{}
+	for _, p := range computeParams {
+		fs.Var(queryFlag{query: query, name: p.name, bool: p.bool}, p.name, p.usage)
+	}
+	lang := fs.String("lang", "", "language of numbers=words, as an Accept-Language header")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(fs.Output(), "unexpected argument %q\n", fs.Arg(0))
+		fs.Usage()
+		return 2
+	}
+
+	header := http.Header{}
+	if *lang != "" {
+		header.Set("Accept-Language", *lang)
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	err := server.Compute(w, query, header)
+	if flushErr := w.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -1,10 +1,25 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"runtime"
+
 	_ "github.com/c-roussel/fizzbuzz-api/docs/swagger"
-	"github.com/c-roussel/fizzbuzz-api/internal/server"
 )
 
+// version is the fizzbuzz-api version, set at build time with
+// -ldflags "-X main.version=...".
+var version = "dev"
+
+const usage = `Usage: fizzbuzz-api [command]
+
+Commands:
+  serve     start the HTTP and gRPC servers (default)
+  compute   print a fizzbuzz sequence, see fizzbuzz-api compute -h
+  version   print the version
+`
+
 // @title FizzBuzz API
 // @version 1.0
 // @description This is a custom FizzBuzz HTTP server.
@@ -19,9 +34,28 @@ import (
 
 // @BasePath /
 // @schemes http
+
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name Authorization
+// @description Bearer token set by FIZZBUZZ_ADMIN_TOKEN, as `Bearer <token>`.
 func main() {
-	e := server.New()
+	cmd, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		cmd, args = args[0], args[1:]
+	}
 
-	e.Logger.Info("Starting fizzbuzz-api server")
-	e.Logger.Fatal(e.Start(":3000"))
+	switch cmd {
+	case "serve":
+		serve()
+	case "compute":
+		os.Exit(compute(args))
+	case "version":
+		fmt.Printf("fizzbuzz-api %s %s\n", version, runtime.Version())
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
 }
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"google.golang.org/grpc"
+)
+
+// shutdownTimeout bounds the time left to ongoing requests when stopping.
+const shutdownTimeout = 10 * time.Second
+
+// serve starts the HTTP and gRPC servers, until SIGINT or SIGTERM.
+func serve() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	e := server.New()
+	g := server.NewGRPC()
+
+	lis, err := net.Listen("tcp", ":3001")
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	go func() {
+		e.Logger.Info("Starting fizzbuzz-api gRPC server")
+		// GracefulStop makes Serve return nil
+		if err := g.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	go func() {
+		e.Logger.Info("Starting fizzbuzz-api server")
+		if err := e.Start(":3000"); !errors.Is(err, http.ErrServerClosed) {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	// Wait for a signal, then let ongoing requests and live streams end
+	<-ctx.Done()
+	e.Logger.Info("Stopping fizzbuzz-api server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		e.Logger.Error(err)
+	}
+	g.GracefulStop()
+}
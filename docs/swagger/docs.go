@@ -25,90 +25,1242 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/stats": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Trash all hits of GET /fizzbuzz/stats, along with the cached responses.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reset /fizzbuzz statistics.",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/stats/denylist": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Get the keys never counted by GET /fizzbuzz/stats.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Denied /fizzbuzz statistics keys.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Never count, cache nor warm a key of GET /fizzbuzz/stats.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Deny a /fizzbuzz statistics key.",
+                "parameters": [
+                    {
+                        "description": "statistics key",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AdminStatsKeyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Count a denied key again in GET /fizzbuzz/stats.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Allow a /fizzbuzz statistics key.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "statistics key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/stats/key": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Get the hits of a GET /fizzbuzz/stats key.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Single /fizzbuzz statistics key.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "statistics key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/stats.Count"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Trash the hits of a GET /fizzbuzz/stats key, along with its cached responses.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a /fizzbuzz statistics key.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "statistics key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
         "/fizzbuzz": {
             "get": {
-                "description": "Get your own version of the fizzbuzz algortihm.",
-                "consumes": [
-                    "*/*"
-                ],
+                "description": "Get your own version of the fizzbuzz algortihm.",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/plain",
+                    "text/csv",
+                    "application/msgpack",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "fizzbuzz"
+                ],
+                "summary": "Customizable fizzbuzz algorithm.",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 3,
+                        "description": "fizzbuzz's first multiple",
+                        "name": "int1",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "fizzbuzz's second multiple",
+                        "name": "int2",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "fizz",
+                        "description": "fizzbuzz's first replacement",
+                        "name": "str1",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "buzz",
+                        "description": "fizzbuzz's second replacement",
+                        "name": "str2",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "fizzbuzz's rules, as divisor:word or kind:arg:word",
+                        "name": "rule",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "fizzbuzz's up-to value",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "fizzbuzz's starting value",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "fizzbuzz's up-to value, replaces limit",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "fizzbuzz's increment",
+                        "name": "step",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "concat",
+                            "first",
+                            "override"
+                        ],
+                        "type": "string",
+                        "default": "concat",
+                        "description": "fizzbuzz's combination of several matching words",
+                        "name": "combine",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "fizzbuzz's separator of concatenated words",
+                        "name": "separator",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "fizzbuzz's combination from the last matching word",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "fizzbuzz's combination overrides, as divisor:word",
+                        "name": "override",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "decimal",
+                        "description": "fizzbuzz's numbers rendering, as decimal, hex, binary, base:N, roman, padded:N or words",
+                        "name": "numbers",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "list",
+                            "periodic"
+                        ],
+                        "type": "string",
+                        "default": "list",
+                        "description": "response's shape, a list of terms or one period of terms",
+                        "name": "shape",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "stream terms as newline delimited JSON",
+                        "name": "stream",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "paginated response's page size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "paginated response's page cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "xml",
+                            "text",
+                            "csv",
+                            "msgpack",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "response's format, overrides Accept header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FizzBuzzOutput"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                },
+                "x-response-budget": {
+                    "applies_to_stream": false,
+                    "default": 67108864,
+                    "env": "FIZZBUZZ_MAX_BYTES"
+                }
+            },
+            "post": {
+                "description": "Get your own version of the fizzbuzz algortihm, with an ordered list of rules.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/plain",
+                    "text/csv",
+                    "application/msgpack",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "fizzbuzz"
+                ],
+                "summary": "Customizable fizzbuzz algorithm.",
+                "parameters": [
+                    {
+                        "description": "fizzbuzz's parameters",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FizzBuzzInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FizzBuzzOutput"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                },
+                "x-response-budget": {
+                    "applies_to_stream": false,
+                    "default": 67108864,
+                    "env": "FIZZBUZZ_MAX_BYTES"
+                }
+            }
+        },
+        "/fizzbuzz/batch": {
+            "post": {
+                "description": "Get many versions of the fizzbuzz algortihm in one call.",
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "fizzbuzz"
+                ],
+                "summary": "Customizable fizzbuzz algorithm, in batch.",
+                "parameters": [
+                    {
+                        "description": "fizzbuzz's parameters",
+                        "name": "inputs",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.FizzBuzzInput"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.FizzBuzzBatchItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                },
+                "x-response-budget": {
+                    "default": 67108864,
+                    "env": "FIZZBUZZ_BATCH_MAX_BYTES"
+                }
+            }
+        },
+        "/fizzbuzz/infer": {
+            "post": {
+                "description": "Find the parameters of a fizzbuzz sequence.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fizzbuzz"
+                ],
+                "summary": "Infer fizzbuzz parameters.",
+                "parameters": [
+                    {
+                        "description": "fizzbuzz's sequence",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FizzBuzzInferInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FizzBuzzInferOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/fizzbuzz/live": {
+            "get": {
+                "description": "Get your own version of the fizzbuzz algortihm, term by term.",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "fizzbuzz"
+                ],
+                "summary": "Customizable fizzbuzz algorithm, live.",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 3,
+                        "description": "fizzbuzz's first multiple",
+                        "name": "int1",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "fizzbuzz's second multiple",
+                        "name": "int2",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "fizz",
+                        "description": "fizzbuzz's first replacement",
+                        "name": "str1",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "buzz",
+                        "description": "fizzbuzz's second replacement",
+                        "name": "str2",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "fizzbuzz's rules, as divisor:word or kind:arg:word",
+                        "name": "rule",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "fizzbuzz's up-to value",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "fizzbuzz's starting value",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "fizzbuzz's up-to value, replaces limit",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "fizzbuzz's increment",
+                        "name": "step",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "concat",
+                            "first",
+                            "override"
+                        ],
+                        "type": "string",
+                        "default": "concat",
+                        "description": "fizzbuzz's combination of several matching words",
+                        "name": "combine",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "fizzbuzz's separator of concatenated words",
+                        "name": "separator",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "fizzbuzz's combination from the last matching word",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "fizzbuzz's combination overrides, as divisor:word",
+                        "name": "override",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "decimal",
+                        "description": "fizzbuzz's numbers rendering, as decimal, hex, binary, base:N, roman, padded:N or words",
+                        "name": "numbers",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0.001,
+                        "type": "number",
+                        "default": 1,
+                        "description": "emitted terms per second",
+                        "name": "rate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "index of the last received term",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    },
+                    "200": {
+                        "description": "term events",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FizzBuzzTermOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/fizzbuzz/stats": {
+            "get": {
+                "description": "Get the 100 most used parameters on GET /fizbuzz route.",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/plain",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "fizzbuzz"
+                ],
+                "summary": "Top 100 /fizzbuzz parameters.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "xml",
+                            "text",
+                            "csv",
+                            "msgpack"
+                        ],
+                        "type": "string",
+                        "description": "response's format, overrides Accept header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/stats.Count"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/fizzbuzz/summary": {
+            "get": {
+                "description": "Count the words and numbers of your own version of the fizzbuzz algortihm.",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "fizzbuzz"
+                ],
+                "summary": "Customizable fizzbuzz algorithm summary.",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 3,
+                        "description": "fizzbuzz's first multiple",
+                        "name": "int1",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "fizzbuzz's second multiple",
+                        "name": "int2",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "fizz",
+                        "description": "fizzbuzz's first replacement",
+                        "name": "str1",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "buzz",
+                        "description": "fizzbuzz's second replacement",
+                        "name": "str2",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "fizzbuzz's divisible rules, as divisor:word",
+                        "name": "rule",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "fizzbuzz's up-to value",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "fizzbuzz's starting value",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "fizzbuzz's up-to value, replaces limit",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "fizzbuzz's increment",
+                        "name": "step",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "concat",
+                            "first"
+                        ],
+                        "type": "string",
+                        "default": "concat",
+                        "description": "fizzbuzz's combination of several matching words",
+                        "name": "combine",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "fizzbuzz's separator of concatenated words",
+                        "name": "separator",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "fizzbuzz's combination from the last matching word",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "xml",
+                            "msgpack"
+                        ],
+                        "type": "string",
+                        "description": "response's format, overrides Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FizzBuzzSummaryOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/fizzbuzz/{n}": {
+            "get": {
+                "description": "Get the value of the n-th term of your own version of the fizzbuzz algortihm.",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "fizzbuzz"
+                ],
+                "summary": "Single fizzbuzz term.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "fizzbuzz's term position",
+                        "name": "n",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 3,
+                        "description": "fizzbuzz's first multiple",
+                        "name": "int1",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "fizzbuzz's second multiple",
+                        "name": "int2",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "fizz",
+                        "description": "fizzbuzz's first replacement",
+                        "name": "str1",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "buzz",
+                        "description": "fizzbuzz's second replacement",
+                        "name": "str2",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "fizzbuzz's rules, as divisor:word or kind:arg:word",
+                        "name": "rule",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "concat",
+                            "first",
+                            "override"
+                        ],
+                        "type": "string",
+                        "default": "concat",
+                        "description": "fizzbuzz's combination of several matching words",
+                        "name": "combine",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "fizzbuzz's separator of concatenated words",
+                        "name": "separator",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "fizzbuzz's combination from the last matching word",
+                        "name": "reverse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "fizzbuzz's combination overrides, as divisor:word",
+                        "name": "override",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "decimal",
+                        "description": "fizzbuzz's numbers rendering, as decimal, hex, binary, base:N, roman, padded:N or words",
+                        "name": "numbers",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "xml",
+                            "msgpack"
+                        ],
+                        "type": "string",
+                        "description": "response's format, overrides Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FizzBuzzTermOutput"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "get": {
+                "description": "Query fizzbuzz terms and stats with GraphQL.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "fizzbuzz"
+                    "graphql"
                 ],
-                "summary": "Customizable fizzbuzz algorithm.",
+                "summary": "GraphQL endpoint.",
                 "parameters": [
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 3,
-                        "description": "fizzbuzz's first multiple",
-                        "name": "int1",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 5,
-                        "description": "fizzbuzz's second multiple",
-                        "name": "int2",
-                        "in": "query"
+                        "type": "string",
+                        "description": "GraphQL query",
+                        "name": "query",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "default": "fizz",
-                        "description": "fizzbuzz's first replacement",
-                        "name": "str1",
+                        "description": "GraphQL operation name",
+                        "name": "operationName",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "default": "buzz",
-                        "description": "fizzbuzz's second replacement",
-                        "name": "str2",
+                        "description": "GraphQL JSON encoded variables",
+                        "name": "variables",
                         "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
                     },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    }
+                },
+                "x-response-budget": {
+                    "default": 67108864,
+                    "env": "FIZZBUZZ_GRAPHQL_MAX_BYTES"
+                }
+            },
+            "post": {
+                "description": "Query fizzbuzz terms and stats with GraphQL.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "graphql"
+                ],
+                "summary": "GraphQL endpoint.",
+                "parameters": [
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "default": 100,
-                        "description": "fizzbuzz's up-to value",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "GraphQL request",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GraphQLInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.FizzBuzzOutput"
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Problem"
                         }
                     }
+                },
+                "x-response-budget": {
+                    "default": 67108864,
+                    "env": "FIZZBUZZ_GRAPHQL_MAX_BYTES"
                 }
             }
         },
-        "/fizzbuzz/stats": {
+        "/graphql/playground": {
             "get": {
-                "description": "Get the 100 most used parameters on GET /fizbuzz route.",
-                "consumes": [
-                    "*/*"
-                ],
+                "description": "Browse the GraphQL schema and run queries.",
                 "produces": [
-                    "application/json"
+                    "text/html"
                 ],
                 "tags": [
-                    "fizzbuzz"
+                    "graphql"
                 ],
-                "summary": "Top 100 /fizzbuzz parameters.",
+                "summary": "GraphQL playground.",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/stats.Count"
-                            }
+                            "type": "string"
                         }
                     }
                 }
@@ -139,9 +1291,197 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "fizzbuzz.Override": {
+            "type": "object",
+            "properties": {
+                "divisor": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "word": {
+                    "type": "string"
+                }
+            }
+        },
+        "fizzbuzz.Rule": {
+            "type": "object",
+            "properties": {
+                "arg": {
+                    "type": "string"
+                },
+                "divisor": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "word": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AdminStatsKeyInput": {
+            "type": "object",
+            "required": [
+                "key"
+            ],
+            "properties": {
+                "key": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.FizzBuzzBatchItem": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.FizzBuzzInferInput": {
+            "type": "object",
+            "required": [
+                "sequence"
+            ],
+            "properties": {
+                "from": {
+                    "type": "integer",
+                    "maximum": 1000000000,
+                    "minimum": -1000000000
+                },
+                "sequence": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.FizzBuzzInferOutput": {
+            "type": "object",
+            "properties": {
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.FizzBuzzInput"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.FizzBuzzInput": {
+            "type": "object",
+            "required": [
+                "from",
+                "int1",
+                "int2",
+                "step",
+                "str1",
+                "str2",
+                "to"
+            ],
+            "properties": {
+                "combine": {
+                    "type": "string",
+                    "enum": [
+                        "concat",
+                        "first",
+                        "override"
+                    ]
+                },
+                "cursor": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "int1": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "int2": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "limit": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "numbers": {
+                    "type": "string"
+                },
+                "overrides": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fizzbuzz.Override"
+                    }
+                },
+                "page_size": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "reverse": {
+                    "type": "boolean"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fizzbuzz.Rule"
+                    }
+                },
+                "separator": {
+                    "type": "string"
+                },
+                "shape": {
+                    "type": "string",
+                    "enum": [
+                        "list",
+                        "periodic"
+                    ]
+                },
+                "step": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "str1": {
+                    "type": "string"
+                },
+                "str2": {
+                    "type": "string"
+                },
+                "stream": {
+                    "type": "boolean"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
         "handlers.FizzBuzzOutput": {
             "type": "object",
             "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                },
                 "result": {
                     "type": "array",
                     "items": {
@@ -150,6 +1490,99 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.FizzBuzzSummaryCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "density": {
+                    "type": "number"
+                },
+                "first": {
+                    "type": "integer"
+                },
+                "matched": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.FizzBuzzSummaryOutput": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "numbers": {
+                    "$ref": "#/definitions/handlers.FizzBuzzSummaryCount"
+                },
+                "words": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.FizzBuzzSummaryCount"
+                    }
+                }
+            }
+        },
+        "handlers.FizzBuzzTermOutput": {
+            "type": "object",
+            "properties": {
+                "matched": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "n": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.GraphQLInput": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "handlers.InvalidParam": {
+            "type": "object",
+            "properties": {
+                "constraint": {
+                    "type": "string",
+                    "example": "min=1"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "int1"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "int1 should be at least 1"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
         "handlers.PingOutput": {
             "type": "object",
             "properties": {
@@ -161,6 +1594,37 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "int1 should be at least 1"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/fizzbuzz?int1=0"
+                },
+                "invalid_params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.InvalidParam"
+                    }
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Invalid parameters"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "/problems/invalid-params"
+                }
+            }
+        },
         "stats.Count": {
             "type": "object",
             "properties": {
@@ -172,6 +1636,14 @@ const docTemplate = `{
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "AdminToken": {
+            "description": "Bearer token set by FIZZBUZZ_ADMIN_TOKEN, as ` + "`" + `Bearer \u003ctoken\u003e` + "`" + `.",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
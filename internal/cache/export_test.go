@@ -0,0 +1,12 @@
+package cache
+
+// Bridge package to expose cache internals
+// Follows the export_test idiom
+
+// ExportSize returns the total size of the values cached in l.
+func ExportSize(l *LRU) int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.size
+}
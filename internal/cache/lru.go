@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Cache metrics, labelled by cache name.
+var (
+	hits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_hits_total",
+		Help: "How many cache lookups found their key.",
+	}, []string{"cache"})
+	misses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_misses_total",
+		Help: "How many cache lookups did not find their key.",
+	}, []string{"cache"})
+	sizes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cache_size_bytes",
+		Help: "Total size of the cached values.",
+	}, []string{"cache"})
+)
+
+func init() {
+	prometheus.MustRegister(hits, misses, sizes)
+}
+
+// LRU is a Least Recently Used cache, bounded by the total size of its
+// values.
+//
+// Its use is:
+//   - Store a value using LRU.Add(key, value, size)
+//   - Retrieve it using LRU.Get(key), counted as a hit or a miss
+//   - Check for a key using LRU.Contains(key), without counting it
+//   - Trash some values using LRU.DeleteFunc(match)
+//   - Trash all values using LRU.Reset()
+type LRU struct {
+	mutex  sync.Mutex
+	name   string
+	budget int
+	size   int
+	order  *list.List
+	items  map[string]*list.Element
+}
+
+type entry struct {
+	key   string
+	value interface{}
+	size  int
+}
+
+// NewLRU will spawn an LRU instance holding up to budget bytes.
+//
+// name labels its metrics, it should be unique.
+func NewLRU(name string, budget int) *LRU {
+	return &LRU{
+		name:   name,
+		budget: budget,
+		order:  list.New(),
+		items:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the key's value, if any, and marks it as recently used.
+func (l *LRU) Get(key string) (interface{}, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	elem, ok := l.items[key]
+	if !ok {
+		misses.WithLabelValues(l.name).Inc()
+		return nil, false
+	}
+
+	hits.WithLabelValues(l.name).Inc()
+	l.order.MoveToFront(elem)
+	return elem.Value.(*entry).value, true
+}
+
+// Contains tells whether the key has a value, without marking it as
+// recently used nor counting a hit or a miss.
+func (l *LRU) Contains(key string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	_, ok := l.items[key]
+	return ok
+}
+
+// Add stores the key's value, size being its size in bytes, evicting least
+// recently used values beyond the budget.
+//
+// Values larger than the whole budget are not stored.
+func (l *LRU) Add(key string, value interface{}, size int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	defer func() {
+		sizes.WithLabelValues(l.name).Set(float64(l.size))
+	}()
+
+	if elem, ok := l.items[key]; ok {
+		l.remove(elem)
+	}
+	if size > l.budget {
+		return
+	}
+
+	l.items[key] = l.order.PushFront(&entry{key: key, value: value, size: size})
+	l.size += size
+	for l.size > l.budget {
+		l.remove(l.order.Back())
+	}
+}
+
+// Len returns the number of cached values.
+func (l *LRU) Len() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return len(l.items)
+}
+
+// DeleteFunc trashes the values match returns true for, and returns how
+// many were trashed.
+func (l *LRU) DeleteFunc(match func(key string, value interface{}) bool) int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	var deleted int
+	for elem := l.order.Front(); elem != nil; {
+		next := elem.Next()
+		if e := elem.Value.(*entry); match(e.key, e.value) {
+			l.remove(elem)
+			deleted++
+		}
+		elem = next
+	}
+	sizes.WithLabelValues(l.name).Set(float64(l.size))
+	return deleted
+}
+
+// Reset trashes all values.
+func (l *LRU) Reset() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.size = 0
+	l.order.Init()
+	l.items = make(map[string]*list.Element)
+	sizes.WithLabelValues(l.name).Set(0)
+}
+
+func (l *LRU) remove(elem *list.Element) {
+	e := l.order.Remove(elem).(*entry)
+	delete(l.items, e.key)
+	l.size -= e.size
+}
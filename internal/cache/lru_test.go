@@ -0,0 +1,154 @@
+package cache_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/cache"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func keys(l *cache.LRU, candidates ...string) []string {
+	found := []string{}
+	for _, key := range candidates {
+		if l.Contains(key) {
+			found = append(found, key)
+		}
+	}
+	return found
+}
+
+func TestLRUEviction(t *testing.T) {
+	t.Run("least recently added", func(t *testing.T) {
+		l := cache.NewLRU("test_eviction_added", 3)
+		l.Add("a", 1, 1)
+		l.Add("b", 2, 1)
+		l.Add("c", 3, 1)
+		l.Add("d", 4, 1)
+
+		td.Cmp(t, keys(l, "a", "b", "c", "d"), []string{"b", "c", "d"})
+		td.Cmp(t, l.Len(), 3)
+	})
+
+	t.Run("least recently got", func(t *testing.T) {
+		l := cache.NewLRU("test_eviction_got", 3)
+		l.Add("a", 1, 1)
+		l.Add("b", 2, 1)
+		l.Add("c", 3, 1)
+
+		v, ok := l.Get("a")
+		td.CmpTrue(t, ok)
+		td.Cmp(t, v, 1)
+
+		l.Add("d", 4, 1)
+		td.Cmp(t, keys(l, "a", "b", "c", "d"), []string{"a", "c", "d"})
+	})
+
+	t.Run("contains does not refresh", func(t *testing.T) {
+		l := cache.NewLRU("test_eviction_contains", 2)
+		l.Add("a", 1, 1)
+		l.Add("b", 2, 1)
+
+		td.CmpTrue(t, l.Contains("a"))
+
+		l.Add("c", 3, 1)
+		td.Cmp(t, keys(l, "a", "b", "c"), []string{"b", "c"})
+	})
+
+	t.Run("re-added key is refreshed", func(t *testing.T) {
+		l := cache.NewLRU("test_eviction_readded", 2)
+		l.Add("a", 1, 1)
+		l.Add("b", 2, 1)
+		l.Add("a", 3, 1)
+		l.Add("c", 4, 1)
+
+		td.Cmp(t, keys(l, "a", "b", "c"), []string{"a", "c"})
+		v, _ := l.Get("a")
+		td.Cmp(t, v, 3)
+	})
+
+	t.Run("as many as needed", func(t *testing.T) {
+		l := cache.NewLRU("test_eviction_many", 10)
+		l.Add("a", 1, 3)
+		l.Add("b", 2, 3)
+		l.Add("c", 3, 3)
+		l.Add("d", 4, 8)
+
+		td.Cmp(t, keys(l, "a", "b", "c", "d"), []string{"d"})
+		td.Cmp(t, cache.ExportSize(l), 8)
+	})
+}
+
+func TestLRUSize(t *testing.T) {
+	t.Run("sums values sizes", func(t *testing.T) {
+		l := cache.NewLRU("test_size_sum", 100)
+		l.Add("a", strings.Repeat("a", 10), 10)
+		l.Add("b", strings.Repeat("b", 20), 20)
+
+		td.Cmp(t, cache.ExportSize(l), 30)
+		td.Cmp(t, l.Len(), 2)
+	})
+
+	t.Run("re-added key replaces its size", func(t *testing.T) {
+		l := cache.NewLRU("test_size_readded", 100)
+		l.Add("a", 1, 10)
+		l.Add("a", 2, 25)
+
+		td.Cmp(t, cache.ExportSize(l), 25)
+		td.Cmp(t, l.Len(), 1)
+	})
+
+	t.Run("fills the whole budget", func(t *testing.T) {
+		l := cache.NewLRU("test_size_budget", 10)
+		l.Add("a", 1, 4)
+		l.Add("b", 2, 6)
+
+		td.Cmp(t, keys(l, "a", "b"), []string{"a", "b"})
+		td.Cmp(t, cache.ExportSize(l), 10)
+	})
+
+	t.Run("larger than the budget", func(t *testing.T) {
+		l := cache.NewLRU("test_size_larger", 10)
+		l.Add("a", 1, 4)
+		l.Add("b", 2, 11)
+
+		td.Cmp(t, keys(l, "a", "b"), []string{"a"})
+		td.Cmp(t, cache.ExportSize(l), 4)
+	})
+
+	t.Run("re-added larger than the budget", func(t *testing.T) {
+		l := cache.NewLRU("test_size_readded_larger", 10)
+		l.Add("a", 1, 4)
+		l.Add("a", 2, 11)
+
+		td.CmpFalse(t, l.Contains("a"))
+		td.Cmp(t, cache.ExportSize(l), 0)
+	})
+
+	t.Run("delete func", func(t *testing.T) {
+		l := cache.NewLRU("test_size_delete", 100)
+		l.Add("a", 1, 10)
+		l.Add("b", 2, 20)
+		l.Add("c", 3, 30)
+
+		deleted := l.DeleteFunc(func(key string, value interface{}) bool {
+			return value.(int)%2 == 1
+		})
+		td.Cmp(t, deleted, 2)
+		td.Cmp(t, keys(l, "a", "b", "c"), []string{"b"})
+		td.Cmp(t, cache.ExportSize(l), 20)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		l := cache.NewLRU("test_size_reset", 100)
+		l.Add("a", 1, 10)
+		l.Add("b", 2, 20)
+		l.Reset()
+
+		td.Cmp(t, l.Len(), 0)
+		td.Cmp(t, cache.ExportSize(l), 0)
+
+		l.Add("c", 3, 100)
+		td.CmpTrue(t, l.Contains("c"))
+	})
+}
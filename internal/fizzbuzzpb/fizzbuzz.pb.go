@@ -0,0 +1,785 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v3.21.12
+// source: fizzbuzz/v1/fizzbuzz.proto
+
+package fizzbuzzpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FizzBuzzRequest holds the same parameters as GET /fizzbuzz, unset fields
+// taking the same default values.
+//
+// The Accept-Language header spelled-out numbers pick their language from
+// is read from the accept-language metadata.
+type FizzBuzzRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Str1  *string `protobuf:"bytes,1,opt,name=str1,proto3,oneof" json:"str1,omitempty"`
+	Str2  *string `protobuf:"bytes,2,opt,name=str2,proto3,oneof" json:"str2,omitempty"`
+	Int1  *int64  `protobuf:"varint,3,opt,name=int1,proto3,oneof" json:"int1,omitempty"`
+	Int2  *int64  `protobuf:"varint,4,opt,name=int2,proto3,oneof" json:"int2,omitempty"`
+	Rules []*Rule `protobuf:"bytes,5,rep,name=rules,proto3" json:"rules,omitempty"`
+	// from and to are decimal integers, they may exceed int64.
+	From      *string     `protobuf:"bytes,6,opt,name=from,proto3,oneof" json:"from,omitempty"`
+	To        *string     `protobuf:"bytes,7,opt,name=to,proto3,oneof" json:"to,omitempty"`
+	Step      *int64      `protobuf:"varint,8,opt,name=step,proto3,oneof" json:"step,omitempty"`
+	Limit     *int64      `protobuf:"varint,9,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+	Combine   string      `protobuf:"bytes,10,opt,name=combine,proto3" json:"combine,omitempty"`
+	Separator string      `protobuf:"bytes,11,opt,name=separator,proto3" json:"separator,omitempty"`
+	Reverse   bool        `protobuf:"varint,12,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	Overrides []*Override `protobuf:"bytes,13,rep,name=overrides,proto3" json:"overrides,omitempty"`
+	Numbers   string      `protobuf:"bytes,14,opt,name=numbers,proto3" json:"numbers,omitempty"`
+}
+
+func (x *FizzBuzzRequest) Reset() {
+	*x = FizzBuzzRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FizzBuzzRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FizzBuzzRequest) ProtoMessage() {}
+
+func (x *FizzBuzzRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FizzBuzzRequest.ProtoReflect.Descriptor instead.
+func (*FizzBuzzRequest) Descriptor() ([]byte, []int) {
+	return file_fizzbuzz_v1_fizzbuzz_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FizzBuzzRequest) GetStr1() string {
+	if x != nil && x.Str1 != nil {
+		return *x.Str1
+	}
+	return ""
+}
+
+func (x *FizzBuzzRequest) GetStr2() string {
+	if x != nil && x.Str2 != nil {
+		return *x.Str2
+	}
+	return ""
+}
+
+func (x *FizzBuzzRequest) GetInt1() int64 {
+	if x != nil && x.Int1 != nil {
+		return *x.Int1
+	}
+	return 0
+}
+
+func (x *FizzBuzzRequest) GetInt2() int64 {
+	if x != nil && x.Int2 != nil {
+		return *x.Int2
+	}
+	return 0
+}
+
+func (x *FizzBuzzRequest) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *FizzBuzzRequest) GetFrom() string {
+	if x != nil && x.From != nil {
+		return *x.From
+	}
+	return ""
+}
+
+func (x *FizzBuzzRequest) GetTo() string {
+	if x != nil && x.To != nil {
+		return *x.To
+	}
+	return ""
+}
+
+func (x *FizzBuzzRequest) GetStep() int64 {
+	if x != nil && x.Step != nil {
+		return *x.Step
+	}
+	return 0
+}
+
+func (x *FizzBuzzRequest) GetLimit() int64 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+func (x *FizzBuzzRequest) GetCombine() string {
+	if x != nil {
+		return x.Combine
+	}
+	return ""
+}
+
+func (x *FizzBuzzRequest) GetSeparator() string {
+	if x != nil {
+		return x.Separator
+	}
+	return ""
+}
+
+func (x *FizzBuzzRequest) GetReverse() bool {
+	if x != nil {
+		return x.Reverse
+	}
+	return false
+}
+
+func (x *FizzBuzzRequest) GetOverrides() []*Override {
+	if x != nil {
+		return x.Overrides
+	}
+	return nil
+}
+
+func (x *FizzBuzzRequest) GetNumbers() string {
+	if x != nil {
+		return x.Numbers
+	}
+	return ""
+}
+
+// Rule replaces the numbers matching its predicate by its word.
+type Rule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind    string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Arg     string `protobuf:"bytes,2,opt,name=arg,proto3" json:"arg,omitempty"`
+	Divisor int64  `protobuf:"varint,3,opt,name=divisor,proto3" json:"divisor,omitempty"`
+	Word    string `protobuf:"bytes,4,opt,name=word,proto3" json:"word,omitempty"`
+}
+
+func (x *Rule) Reset() {
+	*x = Rule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Rule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rule) ProtoMessage() {}
+
+func (x *Rule) ProtoReflect() protoreflect.Message {
+	mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rule.ProtoReflect.Descriptor instead.
+func (*Rule) Descriptor() ([]byte, []int) {
+	return file_fizzbuzz_v1_fizzbuzz_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Rule) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Rule) GetArg() string {
+	if x != nil {
+		return x.Arg
+	}
+	return ""
+}
+
+func (x *Rule) GetDivisor() int64 {
+	if x != nil {
+		return x.Divisor
+	}
+	return 0
+}
+
+func (x *Rule) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+// Override replaces the multiples of its divisor matching several rules by
+// its word.
+type Override struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Divisor int64  `protobuf:"varint,1,opt,name=divisor,proto3" json:"divisor,omitempty"`
+	Word    string `protobuf:"bytes,2,opt,name=word,proto3" json:"word,omitempty"`
+}
+
+func (x *Override) Reset() {
+	*x = Override{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Override) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Override) ProtoMessage() {}
+
+func (x *Override) ProtoReflect() protoreflect.Message {
+	mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Override.ProtoReflect.Descriptor instead.
+func (*Override) Descriptor() ([]byte, []int) {
+	return file_fizzbuzz_v1_fizzbuzz_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Override) GetDivisor() int64 {
+	if x != nil {
+		return x.Divisor
+	}
+	return 0
+}
+
+func (x *Override) GetWord() string {
+	if x != nil {
+		return x.Word
+	}
+	return ""
+}
+
+type ComputeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result []string `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *ComputeResponse) Reset() {
+	*x = ComputeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ComputeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeResponse) ProtoMessage() {}
+
+func (x *ComputeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeResponse.ProtoReflect.Descriptor instead.
+func (*ComputeResponse) Descriptor() ([]byte, []int) {
+	return file_fizzbuzz_v1_fizzbuzz_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ComputeResponse) GetResult() []string {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type StreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Terms []string `protobuf:"bytes,1,rep,name=terms,proto3" json:"terms,omitempty"`
+}
+
+func (x *StreamResponse) Reset() {
+	*x = StreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamResponse) ProtoMessage() {}
+
+func (x *StreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamResponse.ProtoReflect.Descriptor instead.
+func (*StreamResponse) Descriptor() ([]byte, []int) {
+	return file_fizzbuzz_v1_fizzbuzz_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StreamResponse) GetTerms() []string {
+	if x != nil {
+		return x.Terms
+	}
+	return nil
+}
+
+type StatsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *StatsRequest) Reset() {
+	*x = StatsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsRequest) ProtoMessage() {}
+
+func (x *StatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsRequest.ProtoReflect.Descriptor instead.
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return file_fizzbuzz_v1_fizzbuzz_proto_rawDescGZIP(), []int{5}
+}
+
+type StatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Counts []*Count `protobuf:"bytes,1,rep,name=counts,proto3" json:"counts,omitempty"`
+}
+
+func (x *StatsResponse) Reset() {
+	*x = StatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsResponse) ProtoMessage() {}
+
+func (x *StatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsResponse.ProtoReflect.Descriptor instead.
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return file_fizzbuzz_v1_fizzbuzz_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StatsResponse) GetCounts() []*Count {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+// Count is the number of hits of a request key.
+type Count struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Hit int64  `protobuf:"varint,2,opt,name=hit,proto3" json:"hit,omitempty"`
+}
+
+func (x *Count) Reset() {
+	*x = Count{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Count) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Count) ProtoMessage() {}
+
+func (x *Count) ProtoReflect() protoreflect.Message {
+	mi := &file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Count.ProtoReflect.Descriptor instead.
+func (*Count) Descriptor() ([]byte, []int) {
+	return file_fizzbuzz_v1_fizzbuzz_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Count) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Count) GetHit() int64 {
+	if x != nil {
+		return x.Hit
+	}
+	return 0
+}
+
+var File_fizzbuzz_v1_fizzbuzz_proto protoreflect.FileDescriptor
+
+var file_fizzbuzz_v1_fizzbuzz_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x66, 0x69, 0x7a, 0x7a, 0x62, 0x75, 0x7a, 0x7a, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x69,
+	0x7a, 0x7a, 0x62, 0x75, 0x7a, 0x7a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x66, 0x69,
+	0x7a, 0x7a, 0x62, 0x75, 0x7a, 0x7a, 0x2e, 0x76, 0x31, 0x22, 0xe8, 0x03, 0x0a, 0x0f, 0x46, 0x69,
+	0x7a, 0x7a, 0x42, 0x75, 0x7a, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a,
+	0x04, 0x73, 0x74, 0x72, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x73,
+	0x74, 0x72, 0x31, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x73, 0x74, 0x72, 0x32, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x04, 0x73, 0x74, 0x72, 0x32, 0x88, 0x01, 0x01, 0x12,
+	0x17, 0x0a, 0x04, 0x69, 0x6e, 0x74, 0x31, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48, 0x02, 0x52,
+	0x04, 0x69, 0x6e, 0x74, 0x31, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x69, 0x6e, 0x74, 0x32,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x48, 0x03, 0x52, 0x04, 0x69, 0x6e, 0x74, 0x32, 0x88, 0x01,
+	0x01, 0x12, 0x27, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x66, 0x69, 0x7a, 0x7a, 0x62, 0x75, 0x7a, 0x7a, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x17, 0x0a, 0x04, 0x66, 0x72,
+	0x6f, 0x6d, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d,
+	0x88, 0x01, 0x01, 0x12, 0x13, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x05, 0x52, 0x02, 0x74, 0x6f, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x73, 0x74, 0x65, 0x70,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x48, 0x06, 0x52, 0x04, 0x73, 0x74, 0x65, 0x70, 0x88, 0x01,
+	0x01, 0x12, 0x19, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03,
+	0x48, 0x07, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a, 0x07,
+	0x63, 0x6f, 0x6d, 0x62, 0x69, 0x6e, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63,
+	0x6f, 0x6d, 0x62, 0x69, 0x6e, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x70, 0x61, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x70, 0x61, 0x72,
+	0x61, 0x74, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x12, 0x33,
+	0x0a, 0x09, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x66, 0x69, 0x7a, 0x7a, 0x62, 0x75, 0x7a, 0x7a, 0x2e, 0x76, 0x31, 0x2e,
+	0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x52, 0x09, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69,
+	0x64, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x42, 0x07, 0x0a,
+	0x05, 0x5f, 0x73, 0x74, 0x72, 0x31, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x73, 0x74, 0x72, 0x32, 0x42,
+	0x07, 0x0a, 0x05, 0x5f, 0x69, 0x6e, 0x74, 0x31, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x69, 0x6e, 0x74,
+	0x32, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x74,
+	0x6f, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x73, 0x74, 0x65, 0x70, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x22, 0x5a, 0x0a, 0x04, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64,
+	0x12, 0x10, 0x0a, 0x03, 0x61, 0x72, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x61,
+	0x72, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x69, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x07, 0x64, 0x69, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04,
+	0x77, 0x6f, 0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x77, 0x6f, 0x72, 0x64,
+	0x22, 0x38, 0x0a, 0x08, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x64, 0x69, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x64,
+	0x69, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x29, 0x0a, 0x0f, 0x43, 0x6f,
+	0x6d, 0x70, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x26, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x65, 0x72, 0x6d, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x74, 0x65, 0x72, 0x6d, 0x73, 0x22, 0x0e, 0x0a,
+	0x0c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x3b, 0x0a,
+	0x0d, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a,
+	0x0a, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x66, 0x69, 0x7a, 0x7a, 0x62, 0x75, 0x7a, 0x7a, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x52, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x22, 0x2b, 0x0a, 0x05, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x68, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x03, 0x68, 0x69, 0x74, 0x32, 0xdf, 0x01, 0x0a, 0x0f, 0x46, 0x69, 0x7a, 0x7a,
+	0x42, 0x75, 0x7a, 0x7a, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x45, 0x0a, 0x07, 0x43,
+	0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x12, 0x1c, 0x2e, 0x66, 0x69, 0x7a, 0x7a, 0x62, 0x75, 0x7a,
+	0x7a, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x7a, 0x7a, 0x42, 0x75, 0x7a, 0x7a, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x66, 0x69, 0x7a, 0x7a, 0x62, 0x75, 0x7a, 0x7a, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x45, 0x0a, 0x06, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1c, 0x2e, 0x66,
+	0x69, 0x7a, 0x7a, 0x62, 0x75, 0x7a, 0x7a, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x7a, 0x7a, 0x42,
+	0x75, 0x7a, 0x7a, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x66, 0x69, 0x7a,
+	0x7a, 0x62, 0x75, 0x7a, 0x7a, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x3e, 0x0a, 0x05, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x12, 0x19, 0x2e, 0x66, 0x69, 0x7a, 0x7a, 0x62, 0x75, 0x7a, 0x7a, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e,
+	0x66, 0x69, 0x7a, 0x7a, 0x62, 0x75, 0x7a, 0x7a, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x37, 0x5a, 0x35, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x2d, 0x72, 0x6f, 0x75, 0x73, 0x73, 0x65,
+	0x6c, 0x2f, 0x66, 0x69, 0x7a, 0x7a, 0x62, 0x75, 0x7a, 0x7a, 0x2d, 0x61, 0x70, 0x69, 0x2f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x66, 0x69, 0x7a, 0x7a, 0x62, 0x75, 0x7a, 0x7a,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_fizzbuzz_v1_fizzbuzz_proto_rawDescOnce sync.Once
+	file_fizzbuzz_v1_fizzbuzz_proto_rawDescData = file_fizzbuzz_v1_fizzbuzz_proto_rawDesc
+)
+
+func file_fizzbuzz_v1_fizzbuzz_proto_rawDescGZIP() []byte {
+	file_fizzbuzz_v1_fizzbuzz_proto_rawDescOnce.Do(func() {
+		file_fizzbuzz_v1_fizzbuzz_proto_rawDescData = protoimpl.X.CompressGZIP(file_fizzbuzz_v1_fizzbuzz_proto_rawDescData)
+	})
+	return file_fizzbuzz_v1_fizzbuzz_proto_rawDescData
+}
+
+var file_fizzbuzz_v1_fizzbuzz_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_fizzbuzz_v1_fizzbuzz_proto_goTypes = []interface{}{
+	(*FizzBuzzRequest)(nil), // 0: fizzbuzz.v1.FizzBuzzRequest
+	(*Rule)(nil),            // 1: fizzbuzz.v1.Rule
+	(*Override)(nil),        // 2: fizzbuzz.v1.Override
+	(*ComputeResponse)(nil), // 3: fizzbuzz.v1.ComputeResponse
+	(*StreamResponse)(nil),  // 4: fizzbuzz.v1.StreamResponse
+	(*StatsRequest)(nil),    // 5: fizzbuzz.v1.StatsRequest
+	(*StatsResponse)(nil),   // 6: fizzbuzz.v1.StatsResponse
+	(*Count)(nil),           // 7: fizzbuzz.v1.Count
+}
+var file_fizzbuzz_v1_fizzbuzz_proto_depIdxs = []int32{
+	1, // 0: fizzbuzz.v1.FizzBuzzRequest.rules:type_name -> fizzbuzz.v1.Rule
+	2, // 1: fizzbuzz.v1.FizzBuzzRequest.overrides:type_name -> fizzbuzz.v1.Override
+	7, // 2: fizzbuzz.v1.StatsResponse.counts:type_name -> fizzbuzz.v1.Count
+	0, // 3: fizzbuzz.v1.FizzBuzzService.Compute:input_type -> fizzbuzz.v1.FizzBuzzRequest
+	0, // 4: fizzbuzz.v1.FizzBuzzService.Stream:input_type -> fizzbuzz.v1.FizzBuzzRequest
+	5, // 5: fizzbuzz.v1.FizzBuzzService.Stats:input_type -> fizzbuzz.v1.StatsRequest
+	3, // 6: fizzbuzz.v1.FizzBuzzService.Compute:output_type -> fizzbuzz.v1.ComputeResponse
+	4, // 7: fizzbuzz.v1.FizzBuzzService.Stream:output_type -> fizzbuzz.v1.StreamResponse
+	6, // 8: fizzbuzz.v1.FizzBuzzService.Stats:output_type -> fizzbuzz.v1.StatsResponse
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_fizzbuzz_v1_fizzbuzz_proto_init() }
+func file_fizzbuzz_v1_fizzbuzz_proto_init() {
+	if File_fizzbuzz_v1_fizzbuzz_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FizzBuzzRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Rule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Override); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ComputeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Count); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_fizzbuzz_v1_fizzbuzz_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_fizzbuzz_v1_fizzbuzz_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_fizzbuzz_v1_fizzbuzz_proto_goTypes,
+		DependencyIndexes: file_fizzbuzz_v1_fizzbuzz_proto_depIdxs,
+		MessageInfos:      file_fizzbuzz_v1_fizzbuzz_proto_msgTypes,
+	}.Build()
+	File_fizzbuzz_v1_fizzbuzz_proto = out.File
+	file_fizzbuzz_v1_fizzbuzz_proto_rawDesc = nil
+	file_fizzbuzz_v1_fizzbuzz_proto_goTypes = nil
+	file_fizzbuzz_v1_fizzbuzz_proto_depIdxs = nil
+}
@@ -0,0 +1,223 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: fizzbuzz/v1/fizzbuzz.proto
+
+package fizzbuzzpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	FizzBuzzService_Compute_FullMethodName = "/fizzbuzz.v1.FizzBuzzService/Compute"
+	FizzBuzzService_Stream_FullMethodName  = "/fizzbuzz.v1.FizzBuzzService/Stream"
+	FizzBuzzService_Stats_FullMethodName   = "/fizzbuzz.v1.FizzBuzzService/Stats"
+)
+
+// FizzBuzzServiceClient is the client API for FizzBuzzService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FizzBuzzServiceClient interface {
+	// Compute returns the terms of a fizzbuzz range, like GET /fizzbuzz.
+	// Responses estimated above FIZZBUZZ_GRPC_MAX_BYTES fail with
+	// RESOURCE_EXHAUSTED.
+	Compute(ctx context.Context, in *FizzBuzzRequest, opts ...grpc.CallOption) (*ComputeResponse, error)
+	// Stream sends the terms of a fizzbuzz range by chunks, like
+	// GET /fizzbuzz?stream=true, without any byte budget.
+	Stream(ctx context.Context, in *FizzBuzzRequest, opts ...grpc.CallOption) (FizzBuzzService_StreamClient, error)
+	// Stats returns the most used requests, like GET /fizzbuzz/stats.
+	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
+}
+
+type fizzBuzzServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFizzBuzzServiceClient(cc grpc.ClientConnInterface) FizzBuzzServiceClient {
+	return &fizzBuzzServiceClient{cc}
+}
+
+func (c *fizzBuzzServiceClient) Compute(ctx context.Context, in *FizzBuzzRequest, opts ...grpc.CallOption) (*ComputeResponse, error) {
+	out := new(ComputeResponse)
+	err := c.cc.Invoke(ctx, FizzBuzzService_Compute_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fizzBuzzServiceClient) Stream(ctx context.Context, in *FizzBuzzRequest, opts ...grpc.CallOption) (FizzBuzzService_StreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FizzBuzzService_ServiceDesc.Streams[0], FizzBuzzService_Stream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fizzBuzzServiceStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FizzBuzzService_StreamClient interface {
+	Recv() (*StreamResponse, error)
+	grpc.ClientStream
+}
+
+type fizzBuzzServiceStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *fizzBuzzServiceStreamClient) Recv() (*StreamResponse, error) {
+	m := new(StreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fizzBuzzServiceClient) Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
+	out := new(StatsResponse)
+	err := c.cc.Invoke(ctx, FizzBuzzService_Stats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FizzBuzzServiceServer is the server API for FizzBuzzService service.
+// All implementations must embed UnimplementedFizzBuzzServiceServer
+// for forward compatibility
+type FizzBuzzServiceServer interface {
+	// Compute returns the terms of a fizzbuzz range, like GET /fizzbuzz.
+	// Responses estimated above FIZZBUZZ_GRPC_MAX_BYTES fail with
+	// RESOURCE_EXHAUSTED.
+	Compute(context.Context, *FizzBuzzRequest) (*ComputeResponse, error)
+	// Stream sends the terms of a fizzbuzz range by chunks, like
+	// GET /fizzbuzz?stream=true, without any byte budget.
+	Stream(*FizzBuzzRequest, FizzBuzzService_StreamServer) error
+	// Stats returns the most used requests, like GET /fizzbuzz/stats.
+	Stats(context.Context, *StatsRequest) (*StatsResponse, error)
+	mustEmbedUnimplementedFizzBuzzServiceServer()
+}
+
+// UnimplementedFizzBuzzServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedFizzBuzzServiceServer struct {
+}
+
+func (UnimplementedFizzBuzzServiceServer) Compute(context.Context, *FizzBuzzRequest) (*ComputeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Compute not implemented")
+}
+func (UnimplementedFizzBuzzServiceServer) Stream(*FizzBuzzRequest, FizzBuzzService_StreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method Stream not implemented")
+}
+func (UnimplementedFizzBuzzServiceServer) Stats(context.Context, *StatsRequest) (*StatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
+func (UnimplementedFizzBuzzServiceServer) mustEmbedUnimplementedFizzBuzzServiceServer() {}
+
+// UnsafeFizzBuzzServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FizzBuzzServiceServer will
+// result in compilation errors.
+type UnsafeFizzBuzzServiceServer interface {
+	mustEmbedUnimplementedFizzBuzzServiceServer()
+}
+
+func RegisterFizzBuzzServiceServer(s grpc.ServiceRegistrar, srv FizzBuzzServiceServer) {
+	s.RegisterService(&FizzBuzzService_ServiceDesc, srv)
+}
+
+func _FizzBuzzService_Compute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FizzBuzzRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FizzBuzzServiceServer).Compute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FizzBuzzService_Compute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FizzBuzzServiceServer).Compute(ctx, req.(*FizzBuzzRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FizzBuzzService_Stream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FizzBuzzRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FizzBuzzServiceServer).Stream(m, &fizzBuzzServiceStreamServer{stream})
+}
+
+type FizzBuzzService_StreamServer interface {
+	Send(*StreamResponse) error
+	grpc.ServerStream
+}
+
+type fizzBuzzServiceStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *fizzBuzzServiceStreamServer) Send(m *StreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FizzBuzzService_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FizzBuzzServiceServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FizzBuzzService_Stats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FizzBuzzServiceServer).Stats(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FizzBuzzService_ServiceDesc is the grpc.ServiceDesc for FizzBuzzService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FizzBuzzService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "fizzbuzz.v1.FizzBuzzService",
+	HandlerType: (*FizzBuzzServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Compute",
+			Handler:    _FizzBuzzService_Compute_Handler,
+		},
+		{
+			MethodName: "Stats",
+			Handler:    _FizzBuzzService_Stats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			Handler:       _FizzBuzzService_Stream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "fizzbuzz/v1/fizzbuzz.proto",
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/stats"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/labstack/gommon/log"
+)
+
+// FizzBuzzEnvAdminToken is the environment variable setting the bearer
+// token of the /admin routes.
+const FizzBuzzEnvAdminToken = "FIZZBUZZ_ADMIN_TOKEN"
+
+// FizzBuzzEnvAuditLog is the environment variable naming the file admin
+// actions are appended to, instead of stdout.
+const FizzBuzzEnvAuditLog = "FIZZBUZZ_AUDIT_LOG"
+
+// FizzBuzzAdminToken is the bearer token of the /admin routes. They
+// refuse every request while it is empty.
+var FizzBuzzAdminToken string
+
+// auditLog writes a JSON line per admin action.
+var auditLog = log.New("audit")
+
+func init() {
+	FizzBuzzAdminToken = os.Getenv(FizzBuzzEnvAdminToken)
+
+	auditLog.SetLevel(log.INFO)
+	if path := os.Getenv(FizzBuzzEnvAuditLog); path != "" {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			log.Error(
+				"failed to open audit log from env",
+				FizzBuzzEnvAuditLog,
+				err.Error(),
+			)
+			return
+		}
+		auditLog.SetOutput(f)
+	}
+}
+
+// audit records an admin action on key, along with its outcome.
+func audit(c echo.Context, action, key, outcome string) {
+	auditLog.Infoj(log.JSON{
+		"action":    action,
+		"key":       key,
+		"outcome":   outcome,
+		"remote_ip": c.RealIP(),
+		"method":    c.Request().Method,
+		"uri":       c.Request().RequestURI,
+	})
+}
+
+// AdminAuth returns the middleware authenticating /admin requests with
+// an `Authorization: Bearer` header holding FizzBuzzAdminToken.
+//
+// Refused requests are answered with a 401 status and written to the
+// audit log as well.
+func AdminAuth() echo.MiddlewareFunc {
+	return middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+		Validator: func(key string, c echo.Context) (bool, error) {
+			return FizzBuzzAdminToken != "" &&
+				subtle.ConstantTimeCompare([]byte(key), []byte(FizzBuzzAdminToken)) == 1, nil
+		},
+		ErrorHandler: func(err error, c echo.Context) error {
+			c.Logger().Warnf("refused admin request: %v", err)
+			audit(c, "authenticate", "", "unauthorized")
+			c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="admin"`)
+			return echo.ErrUnauthorized
+		},
+	})
+}
+
+// AdminStatsKeyInput names a fizzbuzz statistics key, as listed by
+// GET /fizzbuzz/stats.
+type AdminStatsKeyInput struct {
+	Key string `query:"key" json:"key" validate:"required"`
+}
+
+// bindStatsKey reads and validates the AdminStatsKeyInput of a request.
+func bindStatsKey(c echo.Context) (string, error) {
+	var in AdminStatsKeyInput
+	err := c.Bind(&in)
+	if err != nil {
+		c.Logger().Warnf("failed to parse parameters: %v", err)
+		return "", err
+	}
+
+	err = c.Validate(&in)
+	if err != nil {
+		c.Logger().Warnf("failed to validate parameters: %v", err)
+		return "", err
+	}
+	return in.Key, nil
+}
+
+// AdminStatsReset responds to DELETE /admin/stats HTTP requests.
+//
+// It trashes all fizzbuzz statistics, denied keys excepted, along with
+// the cached and warmed responses, and responds with a 204 HTTP response.
+//
+// @Summary Reset /fizzbuzz statistics.
+// @Description Trash all hits of GET /fizzbuzz/stats, along with the cached responses.
+// @Tags admin
+// @Security AdminToken
+// @Success 204 "No Content"
+// @Failure 401 {object} handlers.Problem
+// @Router /admin/stats [delete]
+func AdminStatsReset(c echo.Context) error {
+	fizzBuzzGatherer.Reset()
+	fizzBuzzWarmer.ForgetAll()
+	audit(c, "reset", "", "ok")
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AdminStatsGet responds to GET /admin/stats/key HTTP requests.
+//
+// It will respond with a 200 HTTP repsonse embedding the stats.Count of
+// the key, or with a 404 HTTP response if it was never hit.
+//
+// @Summary Single /fizzbuzz statistics key.
+// @Description Get the hits of a GET /fizzbuzz/stats key.
+// @Tags admin
+// @Security AdminToken
+// @Param key query string true "statistics key"
+// @Produce json
+// @Success 200 {object} stats.Count
+// @Failure 400 {object} handlers.Problem
+// @Failure 401 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Router /admin/stats/key [get]
+func AdminStatsGet(c echo.Context) error {
+	key, err := bindStatsKey(c)
+	if err != nil {
+		return err
+	}
+
+	hit, ok := fizzBuzzGatherer.Get(key)
+	if !ok {
+		audit(c, "get", key, "not found")
+		return echo.NewHTTPError(http.StatusNotFound, "key was never hit")
+	}
+	audit(c, "get", key, "ok")
+	return c.JSON(http.StatusOK, stats.Count{Key: key, Hit: hit})
+}
+
+// AdminStatsDelete responds to DELETE /admin/stats/key HTTP requests.
+//
+// It trashes the hits of the key, along with its cached and warmed
+// responses, and responds with a 204 HTTP response, or with a 404 HTTP
+// response if it was never hit.
+//
+// @Summary Delete a /fizzbuzz statistics key.
+// @Description Trash the hits of a GET /fizzbuzz/stats key, along with its cached responses.
+// @Tags admin
+// @Security AdminToken
+// @Param key query string true "statistics key"
+// @Success 204 "No Content"
+// @Failure 400 {object} handlers.Problem
+// @Failure 401 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Router /admin/stats/key [delete]
+func AdminStatsDelete(c echo.Context) error {
+	key, err := bindStatsKey(c)
+	if err != nil {
+		return err
+	}
+
+	if !fizzBuzzGatherer.Delete(key) {
+		audit(c, "delete", key, "not found")
+		return echo.NewHTTPError(http.StatusNotFound, "key was never hit")
+	}
+	fizzBuzzWarmer.Forget(key)
+	audit(c, "delete", key, "ok")
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AdminStatsDenylist responds to GET /admin/stats/denylist HTTP requests.
+//
+// It will respond with a 200 HTTP repsonse embedding the alphabetically
+// sorted keys that are never counted.
+//
+// @Summary Denied /fizzbuzz statistics keys.
+// @Description Get the keys never counted by GET /fizzbuzz/stats.
+// @Tags admin
+// @Security AdminToken
+// @Produce json
+// @Success 200 {array} string
+// @Failure 401 {object} handlers.Problem
+// @Router /admin/stats/denylist [get]
+func AdminStatsDenylist(c echo.Context) error {
+	audit(c, "denylist", "", "ok")
+	return c.JSON(http.StatusOK, fizzBuzzGatherer.Denied())
+}
+
+// AdminStatsDeny responds to POST /admin/stats/denylist HTTP requests.
+//
+// It stops counting, caching and warming the key, trashing its previous
+// hits and cached responses, and responds with a 204 HTTP response.
+//
+// @Summary Deny a /fizzbuzz statistics key.
+// @Description Never count, cache nor warm a key of GET /fizzbuzz/stats.
+// @Tags admin
+// @Security AdminToken
+// @Accept json
+// @Param input body handlers.AdminStatsKeyInput true "statistics key"
+// @Success 204 "No Content"
+// @Failure 400 {object} handlers.Problem
+// @Failure 401 {object} handlers.Problem
+// @Router /admin/stats/denylist [post]
+func AdminStatsDeny(c echo.Context) error {
+	key, err := bindStatsKey(c)
+	if err != nil {
+		return err
+	}
+
+	fizzBuzzGatherer.Deny(key)
+	fizzBuzzWarmer.Forget(key)
+	audit(c, "deny", key, "ok")
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AdminStatsAllow responds to DELETE /admin/stats/denylist HTTP requests.
+//
+// It counts the key again and responds with a 204 HTTP response, or with
+// a 404 HTTP response if it was not denied.
+//
+// @Summary Allow a /fizzbuzz statistics key.
+// @Description Count a denied key again in GET /fizzbuzz/stats.
+// @Tags admin
+// @Security AdminToken
+// @Param key query string true "statistics key"
+// @Success 204 "No Content"
+// @Failure 400 {object} handlers.Problem
+// @Failure 401 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Router /admin/stats/denylist [delete]
+func AdminStatsAllow(c echo.Context) error {
+	key, err := bindStatsKey(c)
+	if err != nil {
+		return err
+	}
+
+	if !fizzBuzzGatherer.Allow(key) {
+		audit(c, "allow", key, "not found")
+		return echo.NewHTTPError(http.StatusNotFound, "key is not denied")
+	}
+	audit(c, "allow", key, "ok")
+	return c.NoContent(http.StatusNoContent)
+}
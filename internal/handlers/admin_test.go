@@ -0,0 +1,162 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/handlers"
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"github.com/maxatome/go-testdeep/helpers/tdhttp"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestAdminStats(t *testing.T) {
+	defer func(token string) { handlers.FizzBuzzAdminToken = token }(handlers.FizzBuzzAdminToken)
+	handlers.FizzBuzzAdminToken = "s3cr3t"
+
+	var audit bytes.Buffer
+	handlers.ExportAuditLog.SetOutput(&audit)
+	defer handlers.ExportAuditLog.SetOutput(os.Stdout)
+
+	handlers.ExportFizzBuzzGatherer.Reset()
+	handlers.ExportFizzBuzzWarmer.ForgetAll()
+
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+	auth := http.Header{"Authorization": {"Bearer s3cr3t"}}
+
+	const key = "FizzBuzzInput str1=adm str2=in int1=2 int2=3 limit=6"
+	query := "?key=" + url.QueryEscape(key)
+
+	hit := func() {
+		testAPI.Get("/fizzbuzz?str1=adm&str2=in&int1=2&int2=3&limit=6").
+			CmpStatus(http.StatusOK)
+		// gathering is done asynchronously
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// cached tells whether the key's responses are cached and warmed
+	cached := func(name string, expected bool) {
+		t.Helper()
+		td.Cmp(t, handlers.ExportCachedResponses(handlers.ExportFizzBuzzCache, key) > 0, expected,
+			name+": cached responses")
+		td.Cmp(t, handlers.ExportFizzBuzzWarmer.ExportRemembers(key), expected,
+			name+": warmed input")
+	}
+
+	testAPI.Name("missing token").
+		Delete("/admin/stats", nil).
+		CmpStatus(http.StatusUnauthorized).
+		CmpHeader(td.SuperMapOf(http.Header{
+			"Www-Authenticate": {`Bearer realm="admin"`},
+		}, nil))
+
+	testAPI.Name("invalid token").
+		Delete("/admin/stats", nil, "Authorization", "Bearer nope").
+		CmpStatus(http.StatusUnauthorized)
+
+	hit()
+	hit()
+
+	testAPI.Name("get key").
+		Get("/admin/stats/key"+query, auth).
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`{"key": $1, "hit": 2}`, key))
+	cached("hit key", true)
+
+	testAPI.Name("missing key").
+		Get("/admin/stats/key", auth).
+		CmpStatus(http.StatusBadRequest).
+		CmpJSONBody(td.JSON(`SuperMapOf({"invalid_params": [
+  {"name": "key", "value": "", "constraint": "required", "reason": "key is required"}
+]})`))
+
+	testAPI.Name("delete key").
+		Delete("/admin/stats/key"+query, nil, auth).
+		CmpStatus(http.StatusNoContent).
+		NoBody()
+	cached("deleted key", false)
+
+	testAPI.Name("get deleted key").
+		Get("/admin/stats/key"+query, auth).
+		CmpStatus(http.StatusNotFound).
+		CmpJSONBody(td.JSON(`SuperMapOf({"detail": "key was never hit"})`))
+
+	testAPI.Name("delete deleted key").
+		Delete("/admin/stats/key"+query, nil, auth).
+		CmpStatus(http.StatusNotFound)
+
+	hit()
+
+	cached("hit again key", true)
+
+	testAPI.Name("deny key").
+		PostJSON("/admin/stats/denylist", handlers.AdminStatsKeyInput{Key: key}, auth).
+		CmpStatus(http.StatusNoContent)
+	cached("denied key", false)
+
+	hit()
+	cached("denied key hit", false)
+
+	testAPI.Name("denied key is not counted").
+		Get("/fizzbuzz/stats").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`[]`))
+
+	testAPI.Name("denylist").
+		Get("/admin/stats/denylist", auth).
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`[$1]`, key))
+
+	testAPI.Name("allow key").
+		Delete("/admin/stats/denylist"+query, nil, auth).
+		CmpStatus(http.StatusNoContent)
+
+	testAPI.Name("allow allowed key").
+		Delete("/admin/stats/denylist"+query, nil, auth).
+		CmpStatus(http.StatusNotFound)
+
+	hit()
+	cached("allowed key", true)
+
+	testAPI.Name("reset").
+		Delete("/admin/stats", nil, auth).
+		CmpStatus(http.StatusNoContent)
+	cached("reset key", false)
+
+	testAPI.Name("reset stats").
+		Get("/fizzbuzz/stats").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`[]`))
+
+	var actions []string
+	for _, line := range strings.Split(strings.TrimSpace(audit.String()), "\n") {
+		var entry struct {
+			Prefix, Action, Key, Outcome string
+		}
+		td.Require(t).CmpNoError(json.Unmarshal([]byte(line), &entry), line)
+		td.Cmp(t, entry.Prefix, "audit")
+		if entry.Key != "" {
+			td.Cmp(t, entry.Key, key)
+		}
+		actions = append(actions, entry.Action+" "+entry.Outcome)
+	}
+	td.Cmp(t, actions, []string{
+		"authenticate unauthorized",
+		"authenticate unauthorized",
+		"get ok",
+		"delete ok",
+		"get not found",
+		"delete not found",
+		"deny ok",
+		"denylist ok",
+		"allow ok",
+		"allow not found",
+		"reset ok",
+	})
+}
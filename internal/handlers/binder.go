@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Binder is the echo.Binder plugged to echo webserver.
+//
+// It binds like echo.DefaultBinder, but reports the query parameter or JSON
+// key it failed to bind as an invalid parameter.
+type Binder struct {
+	echo.DefaultBinder
+}
+
+// Bind implements echo.Binder.
+func (b *Binder) Bind(i interface{}, c echo.Context) error {
+	err := b.DefaultBinder.Bind(i, c)
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		constraint := typeName(typeErr.Type)
+		if constraint == "format" {
+			constraint = "object"
+		}
+		return invalidParams(newInvalidParam(typeErr.Field, typeErr.Value, constraint, "type-"+constraint))
+	}
+
+	if p, ok := b.invalidQueryParam(i, c); ok {
+		return invalidParams(p)
+	}
+	return err
+}
+
+// invalidQueryParam binds query parameters one value at a time to find the
+// first one failing to bind.
+func (b *Binder) invalidQueryParam(i interface{}, c echo.Context) (InvalidParam, bool) {
+	query := c.QueryParams()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	typ := reflect.TypeOf(i).Elem()
+	for _, name := range names {
+		for _, value := range query[name] {
+			req := c.Request().Clone(c.Request().Context())
+			req.URL.RawQuery = url.Values{name: {value}}.Encode()
+
+			err := b.BindQueryParams(c.Echo().NewContext(req, nil), reflect.New(typ).Interface())
+			if err == nil {
+				continue
+			}
+
+			constraint := "format"
+			if fld, ok := queryField(typ, name); ok {
+				constraint = typeName(fld.Type)
+			}
+			if constraint == "format" {
+				return InvalidParam{Name: name, Value: value, Constraint: constraint, Reason: formatReason(err)}, true
+			}
+			return newInvalidParam(name, value, constraint, "type-"+constraint), true
+		}
+	}
+	return InvalidParam{}, false
+}
+
+// formatReason is the reason of a parameter failing its UnmarshalParam
+// format, without the details of the strconv error it may wrap.
+func formatReason(err error) string {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Internal != nil {
+		err = httpErr.Internal
+	}
+
+	reason := err.Error()
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		reason = strings.TrimSuffix(reason, ": "+numErr.Error())
+	}
+	return reason
+}
+
+// queryField returns the field of struct type typ bound to the name query
+// parameter.
+func queryField(typ reflect.Type, name string) (reflect.StructField, bool) {
+	if typ.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		fld := typ.Field(i)
+		if fld.Tag.Get("query") == name {
+			return fld, true
+		}
+		if fld.Anonymous {
+			if fld, ok := queryField(fld.Type, name); ok {
+				return fld, true
+			}
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+var bigIntReflectType = reflect.TypeOf(big.Int{})
+
+// typeName names the values a type accepts, "format" for types parsing
+// their own format.
+func typeName(typ reflect.Type) string {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	}
+	if typ == bigIntReflectType {
+		return "integer"
+	}
+	return "format"
+}
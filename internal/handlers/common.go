@@ -1,5 +1,31 @@
 package handlers
 
-import "github.com/c-roussel/fizzbuzz-api/internal/stats"
+import (
+	"os"
+	"strconv"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/stats"
+	"github.com/labstack/gommon/log"
+)
 
 var fizzBuzzGatherer = stats.NewGatherer()
+
+// loadEnvInt overrides value with the name environment variable, if set.
+func loadEnvInt(name string, value *int) {
+	str := os.Getenv(name)
+	if str == "" {
+		return
+	}
+
+	v, err := strconv.Atoi(str)
+	if err != nil {
+		log.Error(
+			"failed to load custom value from env",
+			name,
+			err.Error(),
+		)
+		return
+	}
+
+	*value = v
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"os"
+
+	"github.com/labstack/gommon/log"
+)
+
+// FizzBuzzEnvCursorSecret is the environment variable holding the key
+// used to sign GET /fizzbuzz pagination cursors.
+//
+// When unset, a random key is generated at startup: cursors are then
+// invalidated by a server restart and cannot be shared between instances.
+const FizzBuzzEnvCursorSecret = "FIZZBUZZ_CURSOR_SECRET"
+
+const (
+	cursorOffsetSize      = 8
+	cursorFingerprintSize = 8
+	cursorMACSize         = 16
+	cursorSize            = cursorOffsetSize + cursorFingerprintSize + cursorMACSize
+)
+
+var (
+	errInvalidCursor    = errors.New("invalid cursor")
+	errMismatchedCursor = errors.New("cursor does not match the requested parameters")
+)
+
+var cursorSecret []byte
+
+func init() {
+	if secret := os.Getenv(FizzBuzzEnvCursorSecret); secret != "" {
+		cursorSecret = []byte(secret)
+		return
+	}
+
+	cursorSecret = make([]byte, 32)
+	if _, err := rand.Read(cursorSecret); err != nil {
+		// should never happen
+		log.Fatalf("failed to generate cursor secret: %v", err)
+	}
+}
+
+// cursorFingerprint identifies the normalized input, rules and range
+// included, a cursor was issued for.
+func cursorFingerprint(in FizzBuzzInput) []byte {
+	sum := sha256.Sum256([]byte(in.key()))
+	return sum[:cursorFingerprintSize]
+}
+
+func cursorMAC(payload []byte) []byte {
+	mac := hmac.New(sha256.New, cursorSecret)
+	mac.Write(payload)
+	return mac.Sum(nil)[:cursorMACSize]
+}
+
+// encodeCursor builds an opaque signed cursor pointing at the offset-th
+// term of the input's range.
+func encodeCursor(in FizzBuzzInput, offset uint64) string {
+	buf := make([]byte, cursorOffsetSize, cursorSize)
+	binary.BigEndian.PutUint64(buf, offset)
+	buf = append(buf, cursorFingerprint(in)...)
+	buf = append(buf, cursorMAC(buf)...)
+
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// decodeCursor checks the cursor's signature and that it was issued for
+// the same input, and returns the offset it points at.
+func decodeCursor(in FizzBuzzInput, cursor string) (uint64, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(buf) != cursorSize {
+		return 0, errInvalidCursor
+	}
+
+	payload, mac := buf[:cursorOffsetSize+cursorFingerprintSize], buf[cursorOffsetSize+cursorFingerprintSize:]
+	if !hmac.Equal(mac, cursorMAC(payload)) {
+		return 0, errInvalidCursor
+	}
+
+	if !hmac.Equal(payload[cursorOffsetSize:], cursorFingerprint(in)) {
+		return 0, errMismatchedCursor
+	}
+
+	offset := binary.BigEndian.Uint64(payload)
+	if offset > in.count() {
+		return 0, errInvalidCursor
+	}
+	return offset, nil
+}
+
+// cursorParam describes the invalid cursor a decodeCursor error was caused
+// by.
+func cursorParam(cursor string, err error) InvalidParam {
+	if errors.Is(err, errMismatchedCursor) {
+		return newInvalidParam("cursor", cursor, "same_parameters", "cursor-mismatch")
+	}
+	return newInvalidParam("cursor", cursor, "signed", "cursor-invalid")
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Supported response content types, on top of echo.MIMEApplicationJSON,
+// echo.MIMEApplicationXML and MIMEApplicationNDJSON.
+const (
+	MIMETextPlain          = "text/plain"
+	MIMETextCSV            = "text/csv"
+	MIMEApplicationMsgpack = echo.MIMEApplicationMsgpack
+)
+
+// formats maps the format query parameter values to their content type.
+var formats = map[string]string{
+	"json":    echo.MIMEApplicationJSON,
+	"xml":     echo.MIMEApplicationXML,
+	"text":    MIMETextPlain,
+	"csv":     MIMETextCSV,
+	"msgpack": MIMEApplicationMsgpack,
+	"ndjson":  MIMEApplicationNDJSON,
+}
+
+// tabular is implemented by outputs which can be rendered as CSV or
+// plain text.
+type tabular interface {
+	// header returns the CSV columns names.
+	header() []string
+	// rows returns the CSV records.
+	rows() [][]string
+	// lines returns the plain text lines.
+	lines() []string
+}
+
+// negotiate picks the response content type among offers, from the format
+// query parameter if provided, or from the Accept header otherwise.
+//
+// offers first value is the default one. It returns a 406 HTTP error when
+// no offer is acceptable.
+func negotiate(c echo.Context, format string, offers ...string) (string, error) {
+	return negotiateAccept(c.Request().Header.Get(echo.HeaderAccept), format, offers...)
+}
+
+// negotiateAccept picks the response content type among offers, from
+// format if provided, or from the accept header value otherwise, see
+// negotiate.
+func negotiateAccept(accept, format string, offers ...string) (string, error) {
+	if format != "" {
+		mime, ok := formats[format]
+		if ok && contains(offers, mime) {
+			return mime, nil
+		}
+
+		return "", echo.NewHTTPError(
+			http.StatusNotAcceptable,
+			"unsupported format "+strconv.Quote(format),
+		)
+	}
+
+	if accept == "" {
+		return offers[0], nil
+	}
+
+	for _, mediaRange := range parseAccept(accept) {
+		for _, offer := range offers {
+			if matchMediaRange(mediaRange, offer) {
+				return offer, nil
+			}
+		}
+	}
+
+	return "", echo.NewHTTPError(
+		http.StatusNotAcceptable,
+		"unsupported Accept header "+strconv.Quote(accept),
+	)
+}
+
+// parseAccept returns the acceptable media ranges of an Accept header,
+// ordered by decreasing quality.
+func parseAccept(accept string) []string {
+	type mediaRange struct {
+		value   string
+		quality float64
+	}
+
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		value, params, _ := strings.Cut(part, ";")
+		r := mediaRange{value: strings.TrimSpace(value), quality: 1}
+
+		for _, param := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if k != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(v, 64); err == nil {
+				r.quality = q
+			}
+		}
+
+		if r.value != "" && r.quality > 0 {
+			ranges = append(ranges, r)
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	values := make([]string, len(ranges))
+	for i, r := range ranges {
+		values[i] = r.value
+	}
+	return values
+}
+
+// matchMediaRange tells whether the mime type belongs to the media range,
+// e.g. text/csv belongs to text/csv, text/* and */*.
+func matchMediaRange(mediaRange, mime string) bool {
+	if mediaRange == "*/*" || mediaRange == mime {
+		return true
+	}
+
+	return strings.HasSuffix(mediaRange, "/*") &&
+		strings.HasPrefix(mime, strings.TrimSuffix(mediaRange, "*"))
+}
+
+// render responds with v encoded as the mime content type.
+//
+// v must implement tabular to be rendered as CSV or plain text, see encode.
+func render(c echo.Context, code int, mime string, v interface{}) error {
+	switch mime {
+	case echo.MIMEApplicationXML:
+		return c.XML(code, v)
+	case MIMEApplicationMsgpack, MIMETextCSV, MIMETextPlain:
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentType, contentTypes[mime])
+		res.WriteHeader(code)
+		return encode(res, mime, v)
+	default:
+		return c.JSON(code, v)
+	}
+}
+
+// contentTypes maps the content types encode writes with a charset to
+// their Content-Type header value.
+var contentTypes = map[string]string{
+	MIMEApplicationMsgpack: MIMEApplicationMsgpack,
+	MIMETextCSV:            MIMETextCSV + "; charset=UTF-8",
+	MIMETextPlain:          echo.MIMETextPlainCharsetUTF8,
+}
+
+// encode writes v encoded as the mime content type to w, the same way
+// render responds with it.
+//
+// v must implement tabular to be encoded as CSV, and either tabular or
+// io.WriterTo, e.g. a fizzbuzz.Generator, to be encoded as plain text.
+func encode(w io.Writer, mime string, v interface{}) error {
+	switch mime {
+	case echo.MIMEApplicationXML:
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		return xml.NewEncoder(w).Encode(v)
+	case MIMEApplicationMsgpack:
+		enc := msgpack.NewEncoder(w)
+		enc.SetCustomStructTag("json")
+		return enc.Encode(v)
+	case MIMETextCSV:
+		t := v.(tabular)
+		cw := csv.NewWriter(w)
+		if err := cw.Write(t.header()); err != nil {
+			return err
+		}
+		return cw.WriteAll(t.rows())
+	case MIMETextPlain:
+		if wt, ok := v.(io.WriterTo); ok {
+			_, err := wt.WriteTo(w)
+			return err
+		}
+
+		bw := bufio.NewWriter(w)
+		for _, line := range v.(tabular).lines() {
+			bw.WriteString(line)
+			bw.WriteByte('\n')
+		}
+		return bw.Flush()
+	default:
+		return json.NewEncoder(w).Encode(v)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HTTP caching headers.
+const (
+	HeaderETag         = "ETag"
+	HeaderIfNoneMatch  = "If-None-Match"
+	HeaderCacheControl = "Cache-Control"
+)
+
+// Cache-Control values: fizzbuzz terms never change for a given input
+// while statistics must be revalidated.
+const (
+	cacheControlImmutable  = "public, max-age=31536000, immutable"
+	cacheControlRevalidate = "no-cache"
+)
+
+// varyRepresentation lists the request headers responses depend on.
+const varyRepresentation = "Accept, Accept-Language"
+
+// fizzBuzzETag derives the ETag of a GET /fizzbuzz response from the
+// normalized input and the response's representation.
+//
+// It assumes that the input was validated with validateFizzBuzzInput.
+func fizzBuzzETag(c echo.Context, in FizzBuzzInput, mime string) string {
+	return newETag(fmt.Sprintf("%s shape=%s pretty=%t", in.cacheKey(mime), in.Shape, !cacheable(c)))
+}
+
+// newETag derives a strong entity tag from a representation description.
+func newETag(description string) string {
+	sum := sha256.Sum256([]byte(description))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified sets the response's ETag, Cache-Control and Vary headers, and
+// tells whether the If-None-Match request header matches the ETag, in which
+// case a 304 response should be sent.
+func notModified(c echo.Context, etag, cacheControl string) bool {
+	h := c.Response().Header()
+	h.Set(HeaderETag, etag)
+	h.Set(HeaderCacheControl, cacheControl)
+	h.Set(echo.HeaderVary, varyRepresentation)
+
+	ifNoneMatch := c.Request().Header.Get(HeaderIfNoneMatch)
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	// If-None-Match uses the weak comparison
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,69 @@
 package handlers
 
+import (
+	"os"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/cache"
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
+	"github.com/labstack/echo/v4"
+)
+
 // Bridge package to expose handlers internals
 // Follows the export_test idiom
 
 var ExportFizzBuzzGatherer = fizzBuzzGatherer
+var ExportFizzBuzzCache = fizzBuzzCache
+var ExportFizzBuzzWarmer = fizzBuzzWarmer
+var ExportAuditLog = auditLog
+
+// ExportCachedBody returns the JSON response body of in cached in l, if
+// any.
+func ExportCachedBody(l *cache.LRU, in FizzBuzzInput) (string, bool) {
+	in.numbers, _ = fizzbuzz.ParseNumbers(fizzbuzz.NumbersDecimal, "")
+	v, ok := l.Get(in.cacheKey(echo.MIMEApplicationJSON))
+	if !ok {
+		return "", false
+	}
+	return string(v.(cachedResponse).body), true
+}
+
+// ExportInputs returns the number of inputs remembered by w.
+func (w *FizzBuzzCacheWarmer) ExportInputs() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return len(w.inputs)
+}
+
+// ExportRemembers tells whether w remembers the input of a statistics key.
+func (w *FizzBuzzCacheWarmer) ExportRemembers(key string) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	_, ok := w.inputs[key]
+	return ok
+}
+
+// ExportCachedResponses returns the number of responses cached in l for a
+// statistics key.
+func ExportCachedResponses(l *cache.LRU, key string) int {
+	var count int
+	l.DeleteFunc(func(_ string, value interface{}) bool {
+		if value.(cachedResponse).statsKey == key {
+			count++
+		}
+		return false
+	})
+	return count
+}
+
+// ExportNewRules builds the rules of in, without validating it first.
+func (in FizzBuzzInput) ExportNewRules() error {
+	_, err := in.newRules(echo.New().Logger)
+	return err
+}
+
+// ExportImportCatalogs imports the translation catalogs of dir.
+func ExportImportCatalogs(dir string) error {
+	return translator.importFS(os.DirFS(dir), ".")
+}
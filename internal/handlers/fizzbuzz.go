@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"net/http"
-	"os"
 	"strconv"
+	"strings"
 
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/gommon/log"
 )
 
 // FizzBuzzEnvLimit  is the environment variable to override the servers
@@ -16,6 +20,27 @@ const FizzBuzzEnvLimit = "FIZZBUZZ_MAX_LIMIT"
 
 // FizzBuzzMaxLimit is the maximum threshold for GET /fizzbuzz limit parameter.
 var FizzBuzzMaxLimit = 10000
+
+// Headers of paginated GET /fizzbuzz responses.
+const (
+	HeaderNextCursor = "X-Next-Cursor"
+	HeaderPrevCursor = "X-Prev-Cursor"
+)
+
+// fizzBuzzFormats are the content types GET /fizzbuzz may respond with.
+var fizzBuzzFormats = []string{
+	echo.MIMEApplicationJSON,
+	echo.MIMEApplicationXML,
+	MIMEApplicationMsgpack,
+	MIMETextPlain,
+	MIMETextCSV,
+	MIMEApplicationNDJSON,
+}
+
+// defaultPageSize is the page size of paginated GET /fizzbuzz responses
+// when no page_size parameter is provided.
+const defaultPageSize = 100
+
 var defaultFizzBuzzInput FizzBuzzInput
 
 func init() {
@@ -24,36 +49,99 @@ func init() {
 	defaultStr2 := "buzz"
 	defaultInt1 := 3
 	defaultInt2 := 5
+	defaultStep := 1
 	defaultLimit := 100
 
 	defaultFizzBuzzInput.Str1 = &defaultStr1
 	defaultFizzBuzzInput.Str2 = &defaultStr2
 	defaultFizzBuzzInput.Int1 = &defaultInt1
 	defaultFizzBuzzInput.Int2 = &defaultInt2
+	defaultFizzBuzzInput.From = big.NewInt(1)
+	defaultFizzBuzzInput.Step = &defaultStep
 	defaultFizzBuzzInput.Limit = &defaultLimit
 
-	// setup max FizzBuzzInput.Limit value
-	if envLimitStr := os.Getenv(FizzBuzzEnvLimit); envLimitStr != "" {
-		envLimit, err := strconv.Atoi(envLimitStr)
-		if err != nil {
-			log.Error(
-				"failed to load custom fizzbuzz limit from env",
-				err.Error(),
-			)
-			return
-		}
-
-		FizzBuzzMaxLimit = envLimit
-	}
+	// setup max FizzBuzzInput.Limit values
+	loadEnvInt(FizzBuzzEnvLimit, &FizzBuzzMaxLimit)
+	loadEnvInt(FizzBuzzEnvStreamLimit, &FizzBuzzStreamMaxLimit)
 }
 
 // FizzBuzzInput describes the expected input for the fizzbuzz handler.
+//
+// Str1, Str2, Int1 and Int2 are a shorthand for a two rules list,
+// they cannot be used along with Rules.
+//
+// Limit is a shorthand for To, they cannot be used together. From and To
+// may exceed int64.
+//
+// Combine, Separator, Reverse and Overrides tell which word replaces the
+// numbers matching several rules, see fizzbuzz.Combination.
+//
+// Numbers tells how terms matching no rule are written, see
+// fizzbuzz.ParseNumbers.
+//
+// Shape selects between the list of terms and its periodic representation,
+// see FizzBuzzPeriodicOutput.
+//
+// Cursor and PageSize enable pagination over the From/To range.
 type FizzBuzzInput struct {
-	Str1  *string `query:"str1" validate:"required"`
-	Str2  *string `query:"str2" validate:"required"`
-	Int1  *int    `query:"int1" validate:"required,min=1"`
-	Int2  *int    `query:"int2" validate:"required,min=1"`
-	Limit *int    `query:"limit" validate:"required,min=0"`
+	Str1   *string         `query:"str1" json:"str1" validate:"required"`
+	Str2   *string         `query:"str2" json:"str2" validate:"required"`
+	Int1   *int            `query:"int1" json:"int1" validate:"required,min=1"`
+	Int2   *int            `query:"int2" json:"int2" validate:"required,min=1"`
+	Rules  []fizzbuzz.Rule `query:"rule" json:"rules" validate:"dive"`
+	From   *big.Int        `query:"from" json:"from" validate:"required" swaggertype:"integer"`
+	To     *big.Int        `query:"to" json:"to" validate:"required" swaggertype:"integer"`
+	Step   *int            `query:"step" json:"step" validate:"required,min=1"`
+	Limit  *int            `query:"limit" json:"limit" validate:"omitempty,min=0"`
+	Stream bool            `query:"stream" json:"stream"`
+	Format string          `query:"format" json:"format"`
+
+	Combine   string              `query:"combine" json:"combine" validate:"oneof=concat first override"`
+	Separator string              `query:"separator" json:"separator"`
+	Reverse   bool                `query:"reverse" json:"reverse"`
+	Overrides []fizzbuzz.Override `query:"override" json:"overrides" validate:"dive"`
+
+	Numbers string `query:"numbers" json:"numbers"`
+	Shape   string `query:"shape" json:"shape" validate:"oneof=list periodic"`
+
+	Cursor   string `query:"cursor" json:"cursor"`
+	PageSize *int   `query:"page_size" json:"page_size" validate:"omitempty,min=1"`
+
+	// numbers is the parsed Numbers parameter, set by
+	// validateFizzBuzzInput.
+	numbers fizzbuzz.Numbers
+}
+
+// usesShorthand tells whether any of the two rules shorthand parameters
+// was provided.
+func (in FizzBuzzInput) usesShorthand() bool {
+	return in.Str1 != nil || in.Str2 != nil || in.Int1 != nil || in.Int2 != nil
+}
+
+// rules returns the ordered list of rules described by the input.
+//
+// It assumes that SetDefault method was called on the FizzBuzzInput instance
+// so that all values are non-nil.
+func (in FizzBuzzInput) rules() []fizzbuzz.Rule {
+	if len(in.Rules) > 0 {
+		return in.Rules
+	}
+
+	return []fizzbuzz.Rule{
+		{Divisor: *in.Int1, Word: *in.Str1},
+		{Divisor: *in.Int2, Word: *in.Str2},
+	}
+}
+
+// combination returns how the input combines the words of numbers matching
+// several rules.
+func (in FizzBuzzInput) combination() fizzbuzz.Combination {
+	return fizzbuzz.Combination{
+		Mode:      in.Combine,
+		Separator: in.Separator,
+		Reverse:   in.Reverse,
+		Overrides: in.Overrides,
+	}
 }
 
 // SetDefault converts non-provided inputs to fizzbuzz's algorithm
@@ -71,9 +159,77 @@ func (in *FizzBuzzInput) SetDefault() {
 	if in.Int2 == nil {
 		in.Int2 = defaultFizzBuzzInput.Int2
 	}
-	if in.Limit == nil {
-		in.Limit = defaultFizzBuzzInput.Limit
+	if in.From == nil {
+		in.From = defaultFizzBuzzInput.From
+	}
+	if in.Step == nil {
+		in.Step = defaultFizzBuzzInput.Step
+	}
+	if in.Shape == "" {
+		in.Shape = ShapeList
+	}
+	if in.Combine == "" {
+		in.Combine = fizzbuzz.CombineConcat
+		if len(in.Overrides) > 0 {
+			in.Combine = fizzbuzz.CombineOverride
+		}
+	}
+	if in.To == nil {
+		if in.Limit == nil {
+			in.Limit = defaultFizzBuzzInput.Limit
+		}
+		in.To = big.NewInt(int64(*in.Limit))
+	}
+}
+
+// count returns the number of terms between From and To.
+//
+// It assumes that SetDefault method was called on the FizzBuzzInput instance
+// so that all values are non-nil.
+func (in FizzBuzzInput) count() uint64 {
+	if in.To.Cmp(in.From) < 0 {
+		return 0
+	}
+
+	n := new(big.Int).Sub(in.To, in.From)
+	n.Quo(n, big.NewInt(int64(*in.Step)))
+	if !n.IsUint64() || n.Uint64() == math.MaxUint64 {
+		return math.MaxUint64
+	}
+	return n.Uint64() + 1
+}
+
+// generator iterates over the input's range from its offset-th term.
+//
+// It assumes that the FizzBuzzInput instance was validated so that its
+// range is valid.
+func (in FizzBuzzInput) generator(rs *fizzbuzz.Rules, offset uint64) *fizzbuzz.Generator {
+	g, err := fizzbuzz.NewGenerator(rs, in.From, in.To, *in.Step)
+	if err != nil {
+		// should never happen
+		panic(err)
+	}
+	g.Seek(offset)
+	return g
+}
+
+// paginated tells whether the client asked for a paginated response.
+func (in FizzBuzzInput) paginated() bool {
+	return in.Cursor != "" || in.PageSize != nil
+}
+
+// ruleNames names the rules described by the input: int1 and int2 for the
+// shorthand, their query parameter notation otherwise.
+func (in FizzBuzzInput) ruleNames() []string {
+	if len(in.Rules) == 0 {
+		return []string{"int1", "int2"}
 	}
+
+	names := make([]string, len(in.Rules))
+	for i, r := range in.Rules {
+		names[i] = r.String()
+	}
+	return names
 }
 
 // Register increments the input parameters in fizzbuzz statistics.
@@ -81,15 +237,69 @@ func (in *FizzBuzzInput) SetDefault() {
 // It assumes that SetDefault method was called on the FizzBuzzInput instance
 // so that all values are non-nil.
 func (in FizzBuzzInput) Register() {
-	fizzBuzzGatherer.Hit(
-		fmt.Sprintf("FizzBuzzInput str1=%s str2=%s int1=%d int2=%d limit=%d",
-			*in.Str1, *in.Str2, *in.Int1, *in.Int2, *in.Limit),
-	)
+	fizzBuzzWarmer.Hit(in)
+}
+
+// key normalizes the input as a statistics key.
+//
+// Two rules lists are keyed like the int1/int2/str1/str2 shorthand so that
+// both notations share the same statistics.
+func (in FizzBuzzInput) key() string {
+	var key string
+	if rules := in.rules(); len(rules) == 2 && isShorthand(rules[0]) && isShorthand(rules[1]) {
+		key = fmt.Sprintf("FizzBuzzInput str1=%s str2=%s int1=%d int2=%d",
+			rules[0].Word, rules[1].Word, rules[0].Divisor, rules[1].Divisor)
+	} else {
+		formatted := make([]string, len(rules))
+		for i, r := range rules {
+			formatted[i] = r.String()
+		}
+		key = "FizzBuzzInput rules=" + strings.Join(formatted, ",")
+	}
+
+	// the historical concatenation is left out to keep existing keys
+	if comb := in.combination(); !comb.IsDefault() {
+		key += " " + comb.String()
+	}
+
+	// ranges starting from 1 are keyed like the limit shorthand
+	if in.From.IsInt64() && in.From.Int64() == 1 && *in.Step == 1 {
+		return fmt.Sprintf("%s limit=%d", key, in.To)
+	}
+	return fmt.Sprintf("%s from=%d to=%d step=%d", key, in.From, in.To, *in.Step)
 }
 
 // FizzBuzzOutput describes the response output for the fizzbuzz handler.
+//
+// NextCursor and PrevCursor are only set on paginated responses, when
+// there is a next or previous page.
 type FizzBuzzOutput struct {
-	Result []string `json:"result"`
+	XMLName    xml.Name `json:"-" xml:"fizzbuzz"`
+	Result     []string `json:"result" xml:"result>term"`
+	NextCursor string   `json:"next_cursor,omitempty" xml:"next_cursor,omitempty"`
+	PrevCursor string   `json:"prev_cursor,omitempty" xml:"prev_cursor,omitempty"`
+
+	// from and step describe the numbers behind Result's terms.
+	from *big.Int
+	step int
+}
+
+func (out FizzBuzzOutput) header() []string {
+	return []string{"n", "value"}
+}
+
+func (out FizzBuzzOutput) rows() [][]string {
+	rows := make([][]string, len(out.Result))
+	n, step := new(big.Int).Set(out.from), big.NewInt(int64(out.step))
+	for i, term := range out.Result {
+		rows[i] = []string{n.String(), term}
+		n.Add(n, step)
+	}
+	return rows
+}
+
+func (out FizzBuzzOutput) lines() []string {
+	return out.Result
 }
 
 // FizzBuzz responds to GET /fizbuzz HTTP requests.
@@ -103,6 +313,66 @@ type FizzBuzzOutput struct {
 //  - Each multiple of int2 is replaced by str2
 //  - Each multiple of both is replaced by str1+str2
 //
+// Any number of rules may be provided instead of int1/int2/str1/str2 using
+// repeated `rule=divisor:word` parameters. Multiples of several divisors
+// are replaced by the concatenation of the matching words, in rule order.
+//
+// Numbers matching several rules are replaced according to `combine`:
+//  - concat, the default, concatenates the matching words, joined by
+//    `separator`
+//  - first only keeps the first matching word
+//  - override replaces multiples of an `override=divisor:word` parameter by
+//    its word, matching words being concatenated for other numbers
+// With `reverse=true`, words are concatenated, or picked, from the last
+// matching rule instead.
+//
+// Terms matching no rule are written according to `numbers`: decimal, the
+// default, hex, binary, base:N with N from 2 to 36, roman, padded:N with
+// N digits at least, or words, spelled out in the Accept-Language header's
+// language among English and French.
+//
+// Rules may also use other predicates than divisibility, with
+// `rule=kind:arg:word` parameters, e.g. `rule=contains_digit:3:fizz`.
+// Available kinds are divisible, contains_digit, is_prime, is_square,
+// in_range (with a `min..max` argument) and ends_with.
+//
+// The list may also start from any `from` value, including zero and
+// negative numbers, up to `to` and only keep every `step` value.
+// FizzBuzzMaxLimit then applies to the number of returned values.
+//
+// Values beyond int64, for from, to and rule arguments, are computed with
+// arbitrary precision.
+//
+// When requested with `stream=true` or an `Accept: application/x-ndjson`
+// header, terms are streamed as newline delimited JSON strings instead, up
+// to FizzBuzzStreamMaxLimit terms.
+//
+// When requested with `page_size` or `cursor`, only one page of terms is
+// returned, along with the cursors of the next and previous pages.
+// FizzBuzzMaxLimit then applies to the page size. Cursors are also set as
+// X-Next-Cursor and X-Prev-Cursor headers.
+//
+// When requested with `shape=periodic`, a FizzBuzzPeriodicOutput is
+// returned instead: the terms of one period, which clients expand up to
+// the range's count. FizzBuzzMaxLimit then applies to the period.
+//
+// Unpaginated responses carry an ETag derived from the normalized input
+// along with a long Cache-Control max-age, If-None-Match requests being
+// answered with 304 responses.
+//
+// Responses are kept in a FizzBuzzCacheBytes bounded LRU cache, warmed
+// with the most used inputs by WarmFizzBuzzCache.
+//
+// The response is encoded according to the `format` parameter or the
+// Accept header: JSON, XML, MessagePack, newline separated plain text, or
+// CSV with n and value columns.
+//
+// Responses whose size, estimated from the rules and the range before
+// computing any term, exceeds FizzBuzzMaxBytes are answered with a 413
+// status. Paginated and periodic responses are estimated one page or
+// period at a time, streamed ones are only bound by
+// FizzBuzzStreamMaxLimit.
+//
 // @Summary Customizable fizzbuzz algorithm.
 // @Description Get your own version of the fizzbuzz algortihm.
 // @Tags fizzbuzz
@@ -111,9 +381,29 @@ type FizzBuzzOutput struct {
 // @Param int2  query int    false "fizzbuzz's second multiple"    minimum(1) default(5)
 // @Param str1  query string false "fizzbuzz's first replacement"             default(fizz)
 // @Param str2  query string false "fizzbuzz's second replacement"            default(buzz)
+// @Param rule  query []string false "fizzbuzz's rules, as divisor:word or kind:arg:word" collectionFormat(multi)
 // @Param limit query int    false "fizzbuzz's up-to value"        minimum(0) default(100)
-// @Produce json
+// @Param from  query int    false "fizzbuzz's starting value"                default(1)
+// @Param to    query int    false "fizzbuzz's up-to value, replaces limit"
+// @Param step  query int    false "fizzbuzz's increment"          minimum(1) default(1)
+// @Param combine query string false "fizzbuzz's combination of several matching words" Enums(concat, first, override) default(concat)
+// @Param separator query string false "fizzbuzz's separator of concatenated words"
+// @Param reverse query bool false "fizzbuzz's combination from the last matching word" default(false)
+// @Param override query []string false "fizzbuzz's combination overrides, as divisor:word" collectionFormat(multi)
+// @Param numbers query string false "fizzbuzz's numbers rendering, as decimal, hex, binary, base:N, roman, padded:N or words" default(decimal)
+// @Param shape query string false "response's shape, a list of terms or one period of terms" Enums(list, periodic) default(list)
+// @Param stream query bool  false "stream terms as newline delimited JSON"    default(false)
+// @Param page_size query int false "paginated response's page size"  minimum(1) default(100)
+// @Param cursor query string false "paginated response's page cursor"
+// @Param format query string false "response's format, overrides Accept header" Enums(json, xml, text, csv, msgpack, ndjson)
+// @Produce json,application/xml,plain,text/csv,application/msgpack,application/x-ndjson
+// @Param If-None-Match header string false "ETag of a previous response"
 // @Success 200 {object} handlers.FizzBuzzOutput
+// @Success 304 "Not Modified"
+// @Failure 400 {object} handlers.Problem
+// @Failure 406 {object} handlers.Problem
+// @Failure 413 {object} handlers.Problem
+// @x-response-budget {"env": "FIZZBUZZ_MAX_BYTES", "default": 67108864, "applies_to_stream": false}
 // @Router /fizzbuzz [get]
 func FizzBuzz(c echo.Context) error {
 	var in FizzBuzzInput
@@ -123,59 +413,352 @@ func FizzBuzz(c echo.Context) error {
 		return err
 	}
 
-	in.SetDefault()
+	err = validateFizzBuzzInput(c, &in)
+	if err != nil {
+		return err
+	}
 
-	err = c.Validate(&in)
+	mime, err := in.negotiate(c.Logger(), c.Request().Header.Get(echo.HeaderAccept))
 	if err != nil {
-		c.Logger().Warnf("failed to validate query parameters: %v", err)
 		return err
 	}
 
-	if *in.Limit > FizzBuzzMaxLimit {
-		c.Logger().Warnf("limit %d is higher than threshold %d", in.Limit, FizzBuzzMaxLimit)
-		return echo.NewHTTPError(
-			http.StatusBadRequest,
-			fmt.Sprintf("limit should be lower than %d", FizzBuzzMaxLimit),
-		)
+	rs, err := in.newRules(c.Logger())
+	if err != nil {
+		return err
 	}
 
-	slice := make([]string, *in.Limit)
-	int1, int2, str1, str2 := *in.Int1, *in.Int2, *in.Str1, *in.Str2
-	int3 := lcm(int1, int2)
-	str3 := str1 + str2
+	if in.paginated() {
+		return paginateFizzBuzz(c, in, rs, mime)
+	}
 
-	var v int // avoid v's allocation at every loop
-	for i := range slice {
-		v = i + 1 // array shall start with value 1
-
-		switch {
-		case v%int3 == 0:
-			slice[i] = str3
-		case v%int1 == 0:
-			slice[i] = str1
-		case v%int2 == 0:
-			slice[i] = str2
-		default:
-			// strconv is more efficient than fmt.Sprint
-			slice[i] = strconv.Itoa(v)
+	if in.Shape == ShapePeriodic {
+		return periodicFizzBuzz(c, in, rs, mime)
+	}
+
+	count, err := in.checkCount(c.Logger(), rs, mime)
+	if err != nil {
+		return err
+	}
+
+	// inputs are valid, add this request to fizzbuzz's stats
+	go in.Register()
+
+	if notModified(c, fizzBuzzETag(c, in, mime), cacheControlImmutable) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	if mime == MIMEApplicationNDJSON {
+		return streamFizzBuzz(c, in, rs, count)
+	}
+
+	if !cacheable(c) {
+		return render(c, http.StatusOK, mime, in.listOutput(rs, count, mime))
+	}
+	return renderCached(c, in, mime, func() interface{} {
+		return in.listOutput(rs, count, mime)
+	})
+}
+
+// negotiate picks the content type of the input's response from its format
+// or from the accept header value, checking that its shape, stream and
+// pagination parameters may be used together.
+func (in FizzBuzzInput) negotiate(logger echo.Logger, accept string) (string, error) {
+	periodic := in.Shape == ShapePeriodic
+	if periodic && (in.Stream || in.paginated()) {
+		logger.Warn("periodic shape requested along with stream or pagination")
+		return "", invalidParams(newInvalidParam("shape", in.Shape, "excluded_with=stream cursor page_size", "periodic-exclusive"))
+	}
+
+	if in.Stream {
+		if in.paginated() {
+			logger.Warn("pagination requested along with stream")
+			return "", invalidParams(in.paginationParam("excluded_with=stream", "stream-exclusive"))
 		}
+		return MIMEApplicationNDJSON, nil
+	}
+
+	offers := fizzBuzzFormats
+	if periodic {
+		offers = periodicFormats
+	}
+
+	mime, err := negotiateAccept(accept, in.Format, offers...)
+	if err != nil {
+		logger.Warnf("failed to negotiate response format: %v", err)
+		return "", err
+	}
+
+	if mime == MIMEApplicationNDJSON && in.paginated() {
+		logger.Warn("pagination requested along with stream")
+		return "", invalidParams(in.paginationParam("excluded_with=stream", "stream-exclusive"))
+	}
+	return mime, nil
+}
+
+// paginationParam describes the pagination parameter of the input as
+// invalid, its reason being the key catalog message.
+func (in FizzBuzzInput) paginationParam(constraint, key string) InvalidParam {
+	if in.Cursor != "" {
+		return newInvalidParam("cursor", in.Cursor, constraint, key)
+	}
+	return newInvalidParam("page_size", strconv.Itoa(*in.PageSize), constraint, key)
+}
+
+// newRules builds the rules described by the input.
+//
+// Its error is a bad request naming the parameter the rules were rejected
+// for.
+func (in FizzBuzzInput) newRules(logger echo.Logger) (*fizzbuzz.Rules, error) {
+	rs, err := fizzbuzz.NewRules(in.rules(), in.combination(), in.numbers)
+	if err != nil {
+		logger.Warnf("failed to build rules: %v", err)
+		return nil, invalidParams(in.rulesParam(err))
+	}
+	return rs, nil
+}
+
+// rulesParam describes the parameter a fizzbuzz.NewRules error was caused
+// by.
+func (in FizzBuzzInput) rulesParam(err error) InvalidParam {
+	switch {
+	case errors.Is(err, fizzbuzz.ErrInvalidCombination) && len(in.Overrides) > 0:
+		return newInvalidParam("override", in.overridesValue(), "combination", "invalid", err.Error())
+	case errors.Is(err, fizzbuzz.ErrInvalidCombination):
+		return newInvalidParam("combine", in.Combine, "combination", "invalid", err.Error())
+	default:
+		return newInvalidParam("rule", in.rulesValue(), "rule", "invalid", err.Error())
+	}
+}
+
+// rulesValue returns the input's rules, as the comma separated value of an
+// InvalidParam.
+func (in FizzBuzzInput) rulesValue() string {
+	rules := make([]string, len(in.rules()))
+	for i, r := range in.rules() {
+		rules[i] = r.String()
+	}
+	return strings.Join(rules, ",")
+}
+
+// overridesValue returns the input's overrides, as the comma separated
+// value of an InvalidParam.
+func (in FizzBuzzInput) overridesValue() string {
+	overrides := make([]string, len(in.Overrides))
+	for i, o := range in.Overrides {
+		overrides[i] = o.String()
+	}
+	return strings.Join(overrides, ",")
+}
+
+// checkCount returns the number of terms of the input's whole range, once
+// checked against the servers thresholds of the mime content type.
+//
+// Streamed terms are never held in memory, hence they have no byte
+// budget.
+func (in FizzBuzzInput) checkCount(logger echo.Logger, rs *fizzbuzz.Rules, mime string) (uint64, error) {
+	maxLimit := FizzBuzzMaxLimit
+	if mime == MIMEApplicationNDJSON {
+		maxLimit = FizzBuzzStreamMaxLimit
+	}
+
+	count := in.count()
+	if count > uint64(maxLimit) {
+		logger.Warnf("%d terms is higher than threshold %d", count, maxLimit)
+		threshold := strconv.Itoa(maxLimit)
+		return 0, invalidParams(newInvalidParam("limit", strconv.FormatUint(count, 10), "max="+threshold, "lower-than", threshold))
+	}
+
+	if mime == MIMEApplicationNDJSON {
+		return count, nil
+	}
+
+	err := checkBudget(logger, responseSize(in, rs, 0, count, mime), FizzBuzzMaxBytes)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// listOutput returns the count terms of the input's range, to be rendered
+// as the mime content type.
+//
+// Plain text is written by a fizzbuzz.Generator, one term per line,
+// without building the list of terms.
+func (in FizzBuzzInput) listOutput(rs *fizzbuzz.Rules, count uint64, mime string) interface{} {
+	if mime == MIMETextPlain {
+		return in.generator(rs, 0)
+	}
+	return fizzBuzzOutput(in, rs, 0, count)
+}
+
+// validateFizzBuzzInput checks a bound FizzBuzzInput, setting its default
+// values.
+func validateFizzBuzzInput(c echo.Context, in *FizzBuzzInput) error {
+	if c.Echo().Validator == nil {
+		return echo.ErrValidatorNotRegistered
+	}
+	return in.validate(c.Echo().Validator, c.Logger(), c.Request().Header.Get("Accept-Language"))
+}
+
+// validate checks the input, setting its default values, whatever its
+// transport.
+//
+// acceptLanguage is the Accept-Language header spelled-out numbers pick
+// their language from.
+func (in *FizzBuzzInput) validate(v echo.Validator, logger echo.Logger, acceptLanguage string) error {
+	if len(in.Rules) > 0 && in.usesShorthand() {
+		logger.Warn("rules provided along with int1/int2/str1/str2")
+		return invalidParams(newInvalidParam("rule", in.rulesValue(), "excluded_with=int1 int2 str1 str2", "shorthand-exclusive"))
+	}
+
+	if in.To != nil && in.Limit != nil {
+		logger.Warn("to provided along with limit")
+		return invalidParams(newInvalidParam("to", in.To.String(), "excluded_with=limit", "limit-exclusive"))
+	}
+
+	if len(in.Overrides) > 0 && in.Combine != "" && in.Combine != fizzbuzz.CombineOverride {
+		logger.Warnf("override provided along with combine=%s", in.Combine)
+		return invalidParams(newInvalidParam("override", in.overridesValue(), "excluded_unless=combine override", "combine-exclusive", in.Combine))
+	}
+
+	if len(in.Overrides) == 0 && in.Combine == fizzbuzz.CombineOverride {
+		logger.Warn("combine=override provided without override")
+		return invalidParams(newInvalidParam("combine", in.Combine, "required_with=override", "override-required"))
+	}
+
+	in.SetDefault()
+
+	err := v.Validate(in)
+	if err != nil {
+		logger.Warnf("failed to validate query parameters: %v", err)
+		return err
+	}
+
+	in.numbers, err = fizzbuzz.ParseNumbers(in.Numbers, acceptLanguage)
+	if err != nil {
+		logger.Warnf("failed to parse numbers parameter: %v", err)
+		return invalidParams(numbersParam(in.Numbers))
+	}
+	return nil
+}
+
+// numbersParam describes an invalid numbers parameter, after the mode it
+// names.
+func numbersParam(value string) InvalidParam {
+	mode, _, _ := strings.Cut(value, ":")
+	switch mode {
+	case fizzbuzz.NumbersBase:
+		return newInvalidParam("numbers", value, "format", "numbers-base", value)
+	case fizzbuzz.NumbersPadded:
+		return newInvalidParam("numbers", value, "format", "numbers-padded", value, strconv.Itoa(fizzbuzz.MaxPaddedWidth))
+	case "", fizzbuzz.NumbersDecimal, fizzbuzz.NumbersHex, fizzbuzz.NumbersBinary, fizzbuzz.NumbersRoman, fizzbuzz.NumbersWords:
+		return newInvalidParam("numbers", value, "format", "numbers-argument", mode)
+	default:
+		return newInvalidParam("numbers", value, "format", "numbers-format", value)
+	}
+}
+
+// paginateFizzBuzz responds with the page of terms pointed at by the
+// input's cursor, or the first page if there is none.
+func paginateFizzBuzz(c echo.Context, in FizzBuzzInput, rs *fizzbuzz.Rules, mime string) error {
+	out, err := in.page(c.Logger(), rs, mime)
+	if err != nil {
+		return err
 	}
 
 	// inputs are valid, add this request to fizzbuzz's stats
 	go in.Register()
 
-	return c.JSON(http.StatusOK, FizzBuzzOutput{Result: slice})
+	if out.NextCursor != "" {
+		c.Response().Header().Set(HeaderNextCursor, out.NextCursor)
+	}
+	if out.PrevCursor != "" {
+		c.Response().Header().Set(HeaderPrevCursor, out.PrevCursor)
+	}
+	return render(c, http.StatusOK, mime, out)
+}
+
+// page computes the page of the input's terms its cursor points to, once
+// checked against the servers thresholds.
+func (in FizzBuzzInput) page(logger echo.Logger, rs *fizzbuzz.Rules, mime string) (FizzBuzzOutput, error) {
+	pageSize := defaultPageSize
+	if in.PageSize != nil {
+		pageSize = *in.PageSize
+	}
+
+	if pageSize > FizzBuzzMaxLimit {
+		logger.Warnf("page size %d is higher than threshold %d", pageSize, FizzBuzzMaxLimit)
+		threshold := strconv.Itoa(FizzBuzzMaxLimit)
+		return FizzBuzzOutput{}, invalidParams(newInvalidParam("page_size", strconv.Itoa(pageSize), "max="+threshold, "lower-than", threshold))
+	}
+
+	var offset uint64
+	if in.Cursor != "" {
+		var err error
+		offset, err = decodeCursor(in, in.Cursor)
+		if err != nil {
+			logger.Warnf("failed to decode cursor: %v", err)
+			return FizzBuzzOutput{}, invalidParams(cursorParam(in.Cursor, err))
+		}
+	}
+
+	count, size := in.count(), uint64(pageSize)
+	if remaining := count - offset; remaining < size {
+		size = remaining
+	}
+
+	err := checkBudget(logger, responseSize(in, rs, offset, size, mime), FizzBuzzMaxBytes)
+	if err != nil {
+		return FizzBuzzOutput{}, err
+	}
+
+	out := fizzBuzzOutput(in, rs, offset, size)
+	if offset+size < count {
+		out.NextCursor = encodeCursor(in, offset+size)
+	}
+	if offset > 0 {
+		prev := uint64(0)
+		if offset > uint64(pageSize) {
+			prev = offset - uint64(pageSize)
+		}
+		out.PrevCursor = encodeCursor(in, prev)
+	}
+	return out, nil
 }
 
-// gcd computes the Greatest Common Divisor (GCD) via Euclidean algorithm.
-func gcd(a, b int) int {
-	for b != 0 {
-		a, b = b, a%b
+// fizzBuzzOutput computes size terms of the input's range, starting from
+// the offset-th one.
+func fizzBuzzOutput(in FizzBuzzInput, rs *fizzbuzz.Rules, offset, size uint64) FizzBuzzOutput {
+	slice := make([]string, size)
+
+	g := in.generator(rs, offset)
+	from := g.Value()
+	for i := range slice {
+		slice[i], _ = g.Next()
 	}
-	return a
+	return FizzBuzzOutput{Result: slice, from: from, step: *in.Step}
 }
 
-// lcm computes the Least Common Multiple (LCM) via GCD.
-func lcm(a, b int) int {
-	return a * b / gcd(a, b)
+// FizzBuzzPost responds to POST /fizbuzz HTTP requests.
+//
+// It behaves like FizzBuzz, reading a FizzBuzzInput from a JSON body
+// instead of query parameters.
+//
+// @Summary Customizable fizzbuzz algorithm.
+// @Description Get your own version of the fizzbuzz algortihm, with an ordered list of rules.
+// @Tags fizzbuzz
+// @Accept json
+// @Param input body handlers.FizzBuzzInput false "fizzbuzz's parameters"
+// @Produce json,application/xml,plain,text/csv,application/msgpack,application/x-ndjson
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Success 200 {object} handlers.FizzBuzzOutput
+// @Success 304 "Not Modified"
+// @Failure 400 {object} handlers.Problem
+// @Failure 406 {object} handlers.Problem
+// @Failure 413 {object} handlers.Problem
+// @x-response-budget {"env": "FIZZBUZZ_MAX_BYTES", "default": 67108864, "applies_to_stream": false}
+// @Router /fizzbuzz [post]
+func FizzBuzzPost(c echo.Context) error {
+	return FizzBuzz(c)
 }
@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FizzBuzzEnvBatchLimit is the environment variable to override the
+// servers work budget on POST /fizzbuzz/batch route.
+const FizzBuzzEnvBatchLimit = "FIZZBUZZ_BATCH_MAX_LIMIT"
+
+// FizzBuzzBatchMaxLimit is the maximum number of terms computed by a
+// POST /fizzbuzz/batch request, across all its items.
+//
+// Every item costs its number of terms, and at least one.
+var FizzBuzzBatchMaxLimit = 100000
+
+// FizzBuzzEnvBatchMaxBytes is the environment variable to override the
+// byte budget of POST /fizzbuzz/batch responses.
+const FizzBuzzEnvBatchMaxBytes = "FIZZBUZZ_BATCH_MAX_BYTES"
+
+// FizzBuzzBatchMaxBytes is the byte budget of POST /fizzbuzz/batch
+// responses, across all their items.
+var FizzBuzzBatchMaxBytes = 64 << 20
+
+// FizzBuzzEnvBatchMaxItems is the environment variable to override the
+// maximum number of inputs of a POST /fizzbuzz/batch request.
+const FizzBuzzEnvBatchMaxItems = "FIZZBUZZ_BATCH_MAX_ITEMS"
+
+// FizzBuzzBatchMaxItems is the maximum number of inputs of a
+// POST /fizzbuzz/batch request.
+var FizzBuzzBatchMaxItems = 1000
+
+// FizzBuzzEnvBatchMaxBodyBytes is the environment variable to override the
+// maximum size of POST /fizzbuzz/batch request bodies.
+const FizzBuzzEnvBatchMaxBodyBytes = "FIZZBUZZ_BATCH_MAX_BODY_BYTES"
+
+// FizzBuzzBatchMaxBodyBytes is the maximum size of POST /fizzbuzz/batch
+// request bodies.
+var FizzBuzzBatchMaxBodyBytes = 8 << 20
+
+// maxBatchLineSize is the maximum size of a newline delimited JSON batch
+// item.
+const maxBatchLineSize = 1 << 20
+
+var (
+	errBatchNotArray     = errors.New("batch is not a JSON array")
+	errBatchTooManyItems = errors.New("batch holds too many inputs")
+)
+
+func init() {
+	loadEnvInt(FizzBuzzEnvBatchLimit, &FizzBuzzBatchMaxLimit)
+	loadEnvInt(FizzBuzzEnvBatchMaxBytes, &FizzBuzzBatchMaxBytes)
+	loadEnvInt(FizzBuzzEnvBatchMaxItems, &FizzBuzzBatchMaxItems)
+	loadEnvInt(FizzBuzzEnvBatchMaxBodyBytes, &FizzBuzzBatchMaxBodyBytes)
+}
+
+// batchBudget is what remains of a batch's work and byte budgets.
+type batchBudget struct {
+	terms uint64
+	bytes uint64
+}
+
+// FizzBuzzBatchItem describes the response output of a single input for
+// the fizzbuzz batch handler.
+//
+// Status is the HTTP status the input would have been answered with by
+// POST /fizzbuzz, Result holding its terms on success and Error its error
+// message otherwise.
+type FizzBuzzBatchItem struct {
+	Status int      `json:"status"`
+	Result []string `json:"result,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// FizzBuzzBatch responds to POST /fizbuzz/batch HTTP requests.
+//
+// It will respond with a 200 HTTP repsonse embedding a FizzBuzzBatchItem
+// result per input, in the same order.
+//
+// Inputs are read from a JSON array, or from newline delimited JSON when
+// sent with a `Content-Type: application/x-ndjson` header, in which case
+// the response is newline delimited JSON as well unless the Accept header
+// tells otherwise.
+//
+// Every input is validated and computed the same way as FizzBuzz, invalid
+// ones being answered with an error status without failing the whole
+// batch. Inputs cannot be streamed, paginated nor periodic.
+//
+// Batches are read up to FizzBuzzBatchMaxItems inputs and
+// FizzBuzzBatchMaxBodyBytes bytes, larger ones being answered with a 413
+// status.
+//
+// FizzBuzzMaxLimit applies to every input, and FizzBuzzBatchMaxLimit to
+// the whole batch: inputs beyond it are answered with a 413 status, as
+// are inputs whose result would exceed the remaining FizzBuzzBatchMaxBytes.
+//
+// @Summary Customizable fizzbuzz algorithm, in batch.
+// @Description Get many versions of the fizzbuzz algortihm in one call.
+// @Tags fizzbuzz
+// @Accept json,application/x-ndjson
+// @Param inputs body []handlers.FizzBuzzInput true "fizzbuzz's parameters"
+// @Produce json,application/x-ndjson
+// @Success 200 {array} handlers.FizzBuzzBatchItem
+// @Failure 400 {object} handlers.Problem
+// @Failure 413 {object} handlers.Problem
+// @x-response-budget {"env": "FIZZBUZZ_BATCH_MAX_BYTES", "default": 67108864}
+// @Router /fizzbuzz/batch [post]
+func FizzBuzzBatch(c echo.Context) error {
+	offers := []string{echo.MIMEApplicationJSON, MIMEApplicationNDJSON}
+
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, int64(FizzBuzzBatchMaxBodyBytes))
+
+	var inputs []json.RawMessage
+	var err error
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	switch {
+	case strings.HasPrefix(contentType, MIMEApplicationNDJSON):
+		offers[0], offers[1] = offers[1], offers[0]
+		inputs, err = readBatchLines(body)
+	case strings.HasPrefix(contentType, echo.MIMEApplicationJSON):
+		inputs, err = readBatchArray(body)
+	default:
+		c.Logger().Warnf("unsupported batch content type %q", contentType)
+		return echo.ErrUnsupportedMediaType
+	}
+	switch {
+	case errors.Is(err, errBatchTooManyItems):
+		c.Logger().Warnf("batch holds more than %d inputs", FizzBuzzBatchMaxItems)
+		limit := strconv.Itoa(FizzBuzzBatchMaxItems)
+		httpErr := invalidParams(newInvalidParam("inputs", "", "max="+limit, "max-items", limit))
+		httpErr.Code = http.StatusRequestEntityTooLarge
+		return httpErr
+	case bodyTooLarge(err):
+		c.Logger().Warnf("batch weighs more than %d bytes", FizzBuzzBatchMaxBodyBytes)
+		return echo.NewHTTPError(
+			http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("batch should weigh less than %d bytes", FizzBuzzBatchMaxBodyBytes),
+		)
+	case err != nil:
+		c.Logger().Warnf("failed to parse body: %v", err)
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			"batch should be a JSON array or newline delimited JSON inputs",
+		)
+	}
+
+	if len(inputs) == 0 {
+		c.Logger().Warn("empty batch")
+		return invalidParams(newInvalidParam("inputs", "", "min=1", "min-items", "1"))
+	}
+
+	mime, err := negotiate(c, "", offers...)
+	if err != nil {
+		c.Logger().Warnf("failed to negotiate response format: %v", err)
+		return err
+	}
+
+	budget := batchBudget{
+		terms: uint64(FizzBuzzBatchMaxLimit),
+		bytes: uint64(FizzBuzzBatchMaxBytes),
+	}
+	if mime == MIMEApplicationNDJSON {
+		return streamFizzBuzzBatch(c, inputs, &budget)
+	}
+
+	out := make([]FizzBuzzBatchItem, len(inputs))
+	for i, raw := range inputs {
+		out[i] = fizzBuzzBatchItem(c, raw, &budget)
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+// readBatchArray reads the values of the body's JSON array, up to
+// FizzBuzzBatchMaxItems.
+func readBatchArray(body io.Reader) ([]json.RawMessage, error) {
+	dec := json.NewDecoder(body)
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('[') {
+		return nil, errBatchNotArray
+	}
+
+	var inputs []json.RawMessage
+	for dec.More() {
+		if len(inputs) == FizzBuzzBatchMaxItems {
+			return nil, errBatchTooManyItems
+		}
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, raw)
+	}
+
+	// closing bracket
+	_, err = dec.Token()
+	return inputs, err
+}
+
+// readBatchLines reads the body's newline delimited JSON values, up to
+// FizzBuzzBatchMaxItems, skipping blank lines.
+func readBatchLines(body io.Reader) ([]json.RawMessage, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(nil, maxBatchLineSize)
+
+	var inputs []json.RawMessage
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if len(inputs) == FizzBuzzBatchMaxItems {
+			return nil, errBatchTooManyItems
+		}
+		inputs = append(inputs, append(json.RawMessage(nil), line...))
+	}
+	return inputs, scanner.Err()
+}
+
+// bodyTooLarge tells whether err is the error of an http.MaxBytesReader
+// read beyond its limit.
+//
+// http.MaxBytesError only exists from go1.19.
+func bodyTooLarge(err error) bool {
+	return err != nil && err.Error() == "http: request body too large"
+}
+
+// streamFizzBuzzBatch writes the batch items as newline delimited JSON,
+// flushing every item.
+//
+// It stops as soon as the client disconnects.
+func streamFizzBuzzBatch(c echo.Context, inputs []json.RawMessage, budget *batchBudget) error {
+	ctx := c.Request().Context()
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+	res.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(res)
+	for i, raw := range inputs {
+		if err := ctx.Err(); err != nil {
+			c.Logger().Infof("fizzbuzz batch interrupted after %d items: %v", i, err)
+			return nil
+		}
+
+		if err := enc.Encode(fizzBuzzBatchItem(c, raw, budget)); err != nil {
+			c.Logger().Warnf("failed to stream fizzbuzz batch: %v", err)
+			return nil
+		}
+		res.Flush()
+	}
+	return nil
+}
+
+// fizzBuzzBatchItem computes the batch item of a raw FizzBuzzInput,
+// consuming its terms and bytes from the batch's budget.
+func fizzBuzzBatchItem(c echo.Context, raw json.RawMessage, budget *batchBudget) FizzBuzzBatchItem {
+	var in FizzBuzzInput
+	if err := json.Unmarshal(raw, &in); err != nil {
+		c.Logger().Warnf("failed to parse batch input: %v", err)
+		return FizzBuzzBatchItem{Status: http.StatusBadRequest, Error: err.Error()}
+	}
+
+	if p, ok := in.excludedParam("excluded_with=batch", "batch-exclusive"); ok {
+		c.Logger().Warn("batch input requested with stream, format, pagination or periodic shape")
+		return batchError(c, invalidParams(p))
+	}
+
+	if err := validateFizzBuzzInput(c, &in); err != nil {
+		return batchError(c, err)
+	}
+
+	rs, err := in.newRules(c.Logger())
+	if err != nil {
+		return batchError(c, err)
+	}
+
+	count := in.count()
+	if count > uint64(FizzBuzzMaxLimit) {
+		c.Logger().Warnf("%d terms is higher than threshold %d", count, FizzBuzzMaxLimit)
+		threshold := strconv.Itoa(FizzBuzzMaxLimit)
+		return batchError(c, invalidParams(newInvalidParam("limit", strconv.FormatUint(count, 10), "max="+threshold, "lower-than", threshold)))
+	}
+
+	cost := count
+	if cost == 0 {
+		cost = 1
+	}
+	if cost > budget.terms {
+		c.Logger().Warnf("%d terms is higher than remaining batch budget %d", cost, budget.terms)
+		return FizzBuzzBatchItem{
+			Status: http.StatusRequestEntityTooLarge,
+			Error:  fmt.Sprintf("batch should compute less than %d terms", FizzBuzzBatchMaxLimit),
+		}
+	}
+
+	size := responseSize(in, rs, 0, count, echo.MIMEApplicationJSON)
+	if size > budget.bytes {
+		c.Logger().Warnf("estimated item size %d is higher than remaining batch budget %d", size, budget.bytes)
+		return FizzBuzzBatchItem{
+			Status: http.StatusRequestEntityTooLarge,
+			Error:  budgetMessage(size, FizzBuzzBatchMaxBytes),
+		}
+	}
+	budget.terms -= cost
+	budget.bytes -= size
+
+	// inputs are valid, add this item to fizzbuzz's stats
+	go in.Register()
+
+	return FizzBuzzBatchItem{
+		Status: http.StatusOK,
+		Result: fizzBuzzOutput(in, rs, 0, count).Result,
+	}
+}
+
+// batchError turns a handler error into a batch item, the reasons of
+// invalid parameters being translated according to the Accept-Language
+// header.
+func batchError(c echo.Context, err error) FizzBuzzBatchItem {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		var paramsErr *ParamsError
+		if errors.As(httpErr.Internal, &paramsErr) {
+			locale := translator.find(c.Request().Header.Get("Accept-Language"))
+			return FizzBuzzBatchItem{Status: httpErr.Code, Error: paramsErr.translate(locale).Error()}
+		}
+		return FizzBuzzBatchItem{Status: httpErr.Code, Error: fmt.Sprint(httpErr.Message)}
+	}
+	return FizzBuzzBatchItem{Status: http.StatusInternalServerError, Error: err.Error()}
+}
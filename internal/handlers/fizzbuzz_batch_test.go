@@ -0,0 +1,170 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/handlers"
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"github.com/maxatome/go-testdeep/helpers/tdhttp"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestFizzBuzzBatch(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testCases := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expected       interface{}
+	}{
+		{
+			name:           "valid items",
+			body:           `[{"limit": 5}, {"rules": [{"divisor": 2, "word": "le"}], "from": 3, "to": 4}, {"limit": 0}]`,
+			expectedStatus: http.StatusOK,
+			expected: td.JSON(`[
+				{"status": 200, "result": ["1", "2", "fizz", "4", "buzz"]},
+				{"status": 200, "result": ["3", "le"]},
+				{"status": 200}
+			]`),
+		},
+		{
+			name:           "invalid items",
+			body:           `[{"limit": -1}, {"limit": "six"}, {"limit": 3}, {"stream": true}, {"limit": 10001}]`,
+			expectedStatus: http.StatusOK,
+			expected: td.JSON(`[
+				{"status": 400, "error": "limit should be at least 0"},
+				{"status": 400, "error": "json: cannot unmarshal string into Go struct field FizzBuzzInput.limit of type int"},
+				{"status": 200, "result": ["1", "2", "fizz"]},
+				{"status": 400, "error": "batch inputs cannot use stream, format, cursor, page_size or shape=periodic"},
+				{"status": 400, "error": "limit should be lower than 10000"}
+			]`),
+		},
+		{
+			name:           "empty batch",
+			body:           `[]`,
+			expectedStatus: http.StatusBadRequest,
+			expected:       td.JSON(`SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "inputs", "value": "", "constraint": "min=1", "reason": "inputs should hold at least 1 items"}]})`),
+		},
+		{
+			name:           "not an array",
+			body:           `{"limit": 3}`,
+			expectedStatus: http.StatusBadRequest,
+			expected:       td.JSON(`SuperMapOf({"type": "about:blank", "detail": "batch should be a JSON array or newline delimited JSON inputs"})`),
+		},
+	}
+	for _, tc := range testCases {
+		testAPI.Run(tc.name, func(ta *tdhttp.TestAPI) {
+			ta.Post("/fizzbuzz/batch", strings.NewReader(tc.body), "Content-Type", "application/json").
+				CmpStatus(tc.expectedStatus).
+				CmpJSONBody(tc.expected)
+		})
+	}
+
+	testAPI.Name("ndjson").
+		Post("/fizzbuzz/batch", strings.NewReader("{\"limit\": 2}\n\n{\"limit\": -1}\nnot json\n"),
+			"Content-Type", handlers.MIMEApplicationNDJSON).
+		CmpStatus(http.StatusOK).
+		CmpHeader(td.SuperMapOf(http.Header{"Content-Type": {handlers.MIMEApplicationNDJSON}}, nil)).
+		CmpBody(`{"status":200,"result":["1","2"]}` + "\n" +
+			`{"status":400,"error":"limit should be at least 0"}` + "\n" +
+			`{"status":400,"error":"invalid character 'o' in literal null (expecting 'u')"}` + "\n")
+
+	testAPI.Name("ndjson answered as JSON").
+		Post("/fizzbuzz/batch", strings.NewReader(`{"limit": 2}`),
+			"Content-Type", handlers.MIMEApplicationNDJSON, "Accept", "application/json").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`[{"status": 200, "result": ["1", "2"]}]`))
+
+	testAPI.Name("unsupported content type").
+		Post("/fizzbuzz/batch", strings.NewReader(`limit=2`), "Content-Type", "text/plain").
+		CmpStatus(http.StatusUnsupportedMediaType)
+}
+
+func TestFizzBuzzBatchBudget(t *testing.T) {
+	defer func(limit int) { handlers.FizzBuzzBatchMaxLimit = limit }(handlers.FizzBuzzBatchMaxLimit)
+	handlers.FizzBuzzBatchMaxLimit = 10
+
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testAPI.Post("/fizzbuzz/batch", strings.NewReader(`[{"limit": 6}, {"limit": 5}, {"limit": 3}, {"limit": 0}, {"limit": 0}]`),
+		"Content-Type", "application/json").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`[
+			{"status": 200, "result": ["1", "2", "fizz", "4", "buzz", "fizz"]},
+			{"status": 413, "error": "batch should compute less than 10 terms"},
+			{"status": 200, "result": ["1", "2", "fizz"]},
+			{"status": 200},
+			{"status": 413, "error": "batch should compute less than 10 terms"}
+		]`))
+}
+
+func TestFizzBuzzBatchBytesBudget(t *testing.T) {
+	defer func(bytes int) { handlers.FizzBuzzBatchMaxBytes = bytes }(handlers.FizzBuzzBatchMaxBytes)
+	handlers.FizzBuzzBatchMaxBytes = 100
+
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testAPI.Post("/fizzbuzz/batch", strings.NewReader(`[{"limit": 5}, {"limit": 50}, {"limit": 3}]`),
+		"Content-Type", "application/json").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`[
+			{"status": 200, "result": ["1", "2", "fizz", "4", "buzz"]},
+			{"status": 413, "error": Re("^response would weigh about \\d+ bytes, above the 100 bytes budget")},
+			{"status": 200, "result": ["1", "2", "fizz"]}
+		]`))
+}
+
+func TestFizzBuzzBatchBodyLimits(t *testing.T) {
+	defer func(items, bytes int) {
+		handlers.FizzBuzzBatchMaxItems, handlers.FizzBuzzBatchMaxBodyBytes = items, bytes
+	}(handlers.FizzBuzzBatchMaxItems, handlers.FizzBuzzBatchMaxBodyBytes)
+	handlers.FizzBuzzBatchMaxItems, handlers.FizzBuzzBatchMaxBodyBytes = 2, 64
+
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testAPI.Name("max items").
+		Post("/fizzbuzz/batch", strings.NewReader(`[{"limit": 1}, {"limit": 2}]`), "Content-Type", "application/json").
+		CmpStatus(http.StatusOK)
+
+	tooMany := td.JSON(`SuperMapOf({
+  "type": "/problems/invalid-params",
+  "status": 413,
+  "invalid_params": [
+    {"name": "inputs", "value": "", "constraint": "max=2", "reason": "inputs should hold at most 2 items"}
+  ]
+})`)
+
+	testAPI.Name("too many items").
+		Post("/fizzbuzz/batch", strings.NewReader(`[{"limit": 1}, {"limit": 2}, {"limit": 3}]`), "Content-Type", "application/json").
+		CmpStatus(http.StatusRequestEntityTooLarge).
+		CmpJSONBody(tooMany)
+
+	testAPI.Name("too many ndjson items").
+		Post("/fizzbuzz/batch", strings.NewReader("{}\n{}\n\n{}\n"), "Content-Type", handlers.MIMEApplicationNDJSON).
+		CmpStatus(http.StatusRequestEntityTooLarge).
+		CmpJSONBody(tooMany)
+
+	testAPI.Name("too large body").
+		Post("/fizzbuzz/batch", strings.NewReader(`[{"str1": "`+strings.Repeat("a", 64)+`"}]`), "Content-Type", "application/json").
+		CmpStatus(http.StatusRequestEntityTooLarge).
+		CmpJSONBody(td.JSON(`SuperMapOf({"type": "about:blank", "detail": "batch should weigh less than 64 bytes"})`))
+}
+
+func TestFizzBuzzBatchStats(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testAPI.Post("/fizzbuzz/batch", strings.NewReader(`[{"limit": 7}, {"limit": 7}, {"limit": -1}]`),
+		"Content-Type", "application/json").
+		CmpStatus(http.StatusOK)
+
+	// gathering is done asynchronously
+	time.Sleep(100 * time.Millisecond)
+
+	testAPI.Get("/fizzbuzz/stats").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.Contains(td.JSON(`{"key": "FizzBuzzInput str1=fizz str2=buzz int1=3 int2=5 limit=7", "hit": 2}`)))
+}
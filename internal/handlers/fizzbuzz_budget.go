@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
+	"github.com/labstack/echo/v4"
+)
+
+// FizzBuzzEnvMaxBytes is the environment variable to override the byte
+// budget of GET /fizzbuzz responses.
+const FizzBuzzEnvMaxBytes = "FIZZBUZZ_MAX_BYTES"
+
+// FizzBuzzMaxBytes is the byte budget of GET /fizzbuzz responses:
+// requests whose response is estimated above it are answered with a 413
+// status.
+var FizzBuzzMaxBytes = 64 << 20
+
+// FizzBuzzEnvGraphQLMaxBytes is the environment variable to override the
+// byte budget of GraphQL queries.
+const FizzBuzzEnvGraphQLMaxBytes = "FIZZBUZZ_GRAPHQL_MAX_BYTES"
+
+// FizzBuzzGraphQLMaxBytes is the byte budget of GraphQL queries, shared by
+// all their fizzbuzz fields: queries estimated above it are answered with
+// a 413 status.
+var FizzBuzzGraphQLMaxBytes = 64 << 20
+
+// FizzBuzzEnvGRPCMaxBytes is the environment variable to override the byte
+// budget of gRPC Compute responses.
+const FizzBuzzEnvGRPCMaxBytes = "FIZZBUZZ_GRPC_MAX_BYTES"
+
+// FizzBuzzGRPCMaxBytes is the byte budget of gRPC Compute responses:
+// requests whose response is estimated above it fail with a
+// ResourceExhausted code.
+var FizzBuzzGRPCMaxBytes = 64 << 20
+
+func init() {
+	loadEnvInt(FizzBuzzEnvMaxBytes, &FizzBuzzMaxBytes)
+	loadEnvInt(FizzBuzzEnvGraphQLMaxBytes, &FizzBuzzGraphQLMaxBytes)
+	loadEnvInt(FizzBuzzEnvGRPCMaxBytes, &FizzBuzzGRPCMaxBytes)
+}
+
+// responseSize estimates the size of count terms of the input, from
+// offset, encoded as the mime content type, without computing them.
+func responseSize(in FizzBuzzInput, rs *fizzbuzz.Rules, offset, count uint64, mime string) uint64 {
+	g := in.generator(rs, offset)
+
+	// the widest n of CSV rows is at either end of the range
+	last := new(big.Int).SetUint64(count)
+	last.Mul(last, big.NewInt(int64(*in.Step))).Add(last, g.Value())
+	nLen := len(g.Value().String())
+	if len(last.String()) > nLen {
+		nLen = len(last.String())
+	}
+
+	wordLen, overhead := termEncoding(mime, nLen)
+	size := g.Size(count, wordLen)
+
+	if count > 0 && overhead > (math.MaxUint64-size)/count {
+		return math.MaxUint64
+	}
+	return size + count*overhead
+}
+
+// termEncoding returns how the words of terms are measured once encoded
+// as the mime content type, along with the bytes added to every term.
+//
+// nLen is the length of the widest n column of CSV rows.
+func termEncoding(mime string, nLen int) (func(string) int, uint64) {
+	switch mime {
+	case echo.MIMEApplicationXML:
+		// <term></term>
+		return xmlLen, 13
+	case MIMETextCSV:
+		// the n column, a comma, quotes and a newline
+		return csvLen, uint64(nLen) + 4
+	case MIMEApplicationMsgpack:
+		// the largest string header
+		return nil, 5
+	case MIMETextPlain:
+		return nil, 1
+	case echo.MIMEApplicationProtobuf:
+		// the field tag and the largest length varint
+		return nil, 6
+	default:
+		// quotes, and a comma or a newline
+		return jsonLen, 3
+	}
+}
+
+// jsonLen measures s once encoded as a JSON string, without its quotes.
+func jsonLen(s string) int {
+	b, _ := json.Marshal(s)
+	return len(b) - 2
+}
+
+// xmlLen measures s once escaped as XML text.
+func xmlLen(s string) int {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.Len()
+}
+
+// csvLen measures s once written as a quoted CSV field, without its
+// quotes.
+func csvLen(s string) int {
+	return len(s) + strings.Count(s, `"`)
+}
+
+// checkBudget returns a 413 HTTP error when the estimated size of a
+// response exceeds its budget.
+func checkBudget(logger echo.Logger, size uint64, budget int) error {
+	if size <= uint64(budget) {
+		return nil
+	}
+
+	logger.Warnf("estimated response size %d is higher than budget %d", size, budget)
+	return echo.NewHTTPError(
+		http.StatusRequestEntityTooLarge,
+		budgetMessage(size, budget),
+	)
+}
+
+// budgetMessage explains why a response exceeding its budget is refused.
+func budgetMessage(size uint64, budget int) string {
+	return fmt.Sprintf(
+		"response would weigh about %d bytes, above the %d bytes budget: lower limit, page_size or words length",
+		size, budget,
+	)
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/cache"
+	"github.com/c-roussel/fizzbuzz-api/internal/stats"
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
+	"github.com/labstack/echo/v4"
+)
+
+// FizzBuzzEnvCacheBytes is the environment variable to override the byte
+// budget of GET /fizzbuzz responses cache.
+const FizzBuzzEnvCacheBytes = "FIZZBUZZ_CACHE_BYTES"
+
+// FizzBuzzCacheBytes is the byte budget of GET /fizzbuzz responses cache,
+// 0 disables it.
+var FizzBuzzCacheBytes = 32 << 20
+
+// FizzBuzzCacheWarmSize is the number of most used inputs cached by
+// WarmFizzBuzzCache.
+const FizzBuzzCacheWarmSize = 100
+
+// FizzBuzzEnvCacheWarmInterval is the environment variable to override the
+// interval between two warm-ups of GET /fizzbuzz responses cache.
+const FizzBuzzEnvCacheWarmInterval = "FIZZBUZZ_CACHE_WARM_INTERVAL"
+
+// FizzBuzzCacheWarmInterval is the number of seconds between two warm-ups
+// of GET /fizzbuzz responses cache, 0 disables them.
+var FizzBuzzCacheWarmInterval = 60
+
+var fizzBuzzCache = newFizzBuzzCache()
+
+var fizzBuzzWarmer = NewFizzBuzzCacheWarmer(fizzBuzzGatherer, fizzBuzzCache, FizzBuzzCacheWarmSize)
+
+func newFizzBuzzCache() *cache.LRU {
+	loadEnvInt(FizzBuzzEnvCacheBytes, &FizzBuzzCacheBytes)
+	loadEnvInt(FizzBuzzEnvCacheWarmInterval, &FizzBuzzCacheWarmInterval)
+	return cache.NewLRU("fizzbuzz", FizzBuzzCacheBytes)
+}
+
+// cachedResponse is a serialized GET /fizzbuzz response.
+type cachedResponse struct {
+	// statsKey is the statistics key of the response's input.
+	statsKey    string
+	contentType string
+	body        []byte
+}
+
+// cacheKey normalizes the input along with the response content type as a
+// cache key.
+func (in FizzBuzzInput) cacheKey(mime string) string {
+	return fmt.Sprintf("%s numbers=%+v mime=%s", in.key(), in.numbers, mime)
+}
+
+// cacheable tells whether the response may be cached: pretty responses are
+// not.
+func cacheable(c echo.Context) bool {
+	_, pretty := c.QueryParams()["pretty"]
+	return !pretty && !c.Echo().Debug
+}
+
+// renderCached responds with the cached response of the input if any, or
+// with v encoded as the mime content type otherwise, caching it unless the
+// input is denied from statistics.
+func renderCached(c echo.Context, in FizzBuzzInput, mime string, v func() interface{}) error {
+	key := in.cacheKey(mime)
+	if cached, ok := fizzBuzzCache.Get(key); ok {
+		res := cached.(cachedResponse)
+		return c.Blob(http.StatusOK, res.contentType, res.body)
+	}
+
+	res := c.Response()
+	w := &captureWriter{ResponseWriter: res.Writer}
+	res.Writer = w
+	defer func() { res.Writer = w.ResponseWriter }()
+
+	if err := render(c, http.StatusOK, mime, v()); err != nil {
+		return err
+	}
+
+	statsKey := in.key()
+	if fizzBuzzGatherer.IsDenied(statsKey) {
+		return nil
+	}
+	fizzBuzzCache.Add(key, cachedResponse{
+		statsKey:    statsKey,
+		contentType: res.Header().Get(echo.HeaderContentType),
+		body:        w.body.Bytes(),
+	}, len(key)+w.body.Len())
+	return nil
+}
+
+// captureWriter copies the written response body.
+type captureWriter struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// FizzBuzzCacheWarmer counts inputs in fizzbuzz statistics, and caches
+// the responses of the most used ones.
+//
+// It only remembers the inputs of the most used statistics keys, so that
+// its memory is bounded whatever the traffic.
+type FizzBuzzCacheWarmer struct {
+	gatherer *stats.Gatherer
+	cache    *cache.LRU
+	size     int
+
+	mutex  sync.Mutex
+	inputs map[string]FizzBuzzInput
+}
+
+// NewFizzBuzzCacheWarmer will spawn a FizzBuzzCacheWarmer instance,
+// caching the responses of the size most used inputs of g in c.
+func NewFizzBuzzCacheWarmer(g *stats.Gatherer, c *cache.LRU, size int) *FizzBuzzCacheWarmer {
+	return &FizzBuzzCacheWarmer{
+		gatherer: g,
+		cache:    c,
+		size:     size,
+		inputs:   make(map[string]FizzBuzzInput),
+	}
+}
+
+// Hit increments the input in statistics and remembers it, until it falls
+// out of the most used ones. Denied inputs are never remembered.
+//
+// It assumes that SetDefault method was called on the FizzBuzzInput instance
+// so that all values are non-nil.
+func (w *FizzBuzzCacheWarmer) Hit(in FizzBuzzInput) {
+	key := in.key()
+	w.gatherer.Hit(key)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if _, ok := w.inputs[key]; ok || w.gatherer.IsDenied(key) {
+		return
+	}
+	w.inputs[key] = in
+
+	// inputs are pruned down to the most used ones once twice as many
+	if len(w.inputs) > 2*w.size {
+		values := w.gatherer.OrderedValues()
+		kept := make(map[string]FizzBuzzInput, w.size)
+		for _, count := range values[:min(w.size, len(values))] {
+			if in, ok := w.inputs[count.Key]; ok {
+				kept[count.Key] = in
+			}
+		}
+		w.inputs = kept
+	}
+}
+
+// Warm caches the JSON responses of the most used inputs, unless already
+// cached.
+//
+// Inputs beyond FizzBuzzMaxLimit terms are skipped.
+func (w *FizzBuzzCacheWarmer) Warm() {
+	values := w.gatherer.OrderedValues()
+	for _, count := range values[:min(w.size, len(values))] {
+		w.mutex.Lock()
+		in, ok := w.inputs[count.Key]
+		w.mutex.Unlock()
+		if !ok {
+			continue
+		}
+
+		// keyed like validated inputs, whose numbers are always parsed
+		in.numbers, _ = fizzbuzz.ParseNumbers(fizzbuzz.NumbersDecimal, "")
+		key := in.cacheKey(echo.MIMEApplicationJSON)
+		if in.count() > uint64(FizzBuzzMaxLimit) || w.cache.Contains(key) {
+			continue
+		}
+
+		rs, err := fizzbuzz.NewRules(in.rules(), in.combination(), in.numbers)
+		if err != nil {
+			continue
+		}
+
+		// encoded the same way as echo's default JSON serializer
+		body, err := json.Marshal(fizzBuzzOutput(in, rs, 0, in.count()))
+		if err != nil {
+			continue
+		}
+		body = append(body, '\n')
+
+		w.cache.Add(key, cachedResponse{
+			statsKey:    count.Key,
+			contentType: echo.MIMEApplicationJSONCharsetUTF8,
+			body:        body,
+		}, len(key)+len(body))
+	}
+}
+
+// Forget trashes the input of a statistics key, along with its cached
+// responses, whatever their content type.
+func (w *FizzBuzzCacheWarmer) Forget(key string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	delete(w.inputs, key)
+	w.cache.DeleteFunc(func(_ string, value interface{}) bool {
+		return value.(cachedResponse).statsKey == key
+	})
+}
+
+// ForgetAll trashes all inputs, along with all cached responses.
+func (w *FizzBuzzCacheWarmer) ForgetAll() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.inputs = make(map[string]FizzBuzzInput)
+	w.cache.Reset()
+}
+
+// Run warms the cache right away, then every interval, until stop is
+// called.
+func (w *FizzBuzzCacheWarmer) Run(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		w.Warm()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.Warm()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// WarmFizzBuzzCache warms GET /fizzbuzz responses cache with the
+// FizzBuzzCacheWarmSize most used inputs when the server starts, then
+// every FizzBuzzCacheWarmInterval seconds, until stop is called.
+func WarmFizzBuzzCache() (stop func()) {
+	if FizzBuzzCacheWarmInterval <= 0 {
+		return func() {}
+	}
+	return fizzBuzzWarmer.Run(time.Duration(FizzBuzzCacheWarmInterval) * time.Second)
+}
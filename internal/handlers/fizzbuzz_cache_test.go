@@ -0,0 +1,160 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/cache"
+	"github.com/c-roussel/fizzbuzz-api/internal/handlers"
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"github.com/c-roussel/fizzbuzz-api/internal/stats"
+	"github.com/maxatome/go-testdeep/helpers/tdhttp"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestFizzBuzzCache(t *testing.T) {
+	handlers.ExportFizzBuzzGatherer.Reset()
+	handlers.ExportFizzBuzzCache.Reset()
+
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	var body string
+	testAPI.Name("cache miss").
+		Get("/fizzbuzz?str1=warm&limit=6").
+		CmpStatus(http.StatusOK).
+		CmpBody(td.Catch(&body, td.Ignore()))
+	td.Cmp(t, handlers.ExportFizzBuzzCache.Len(), 1)
+
+	testAPI.Name("cache hit").
+		Get("/fizzbuzz?str1=warm&limit=6").
+		CmpStatus(http.StatusOK).
+		CmpHeader(td.SuperMapOf(http.Header{
+			"Content-Type": {"application/json; charset=UTF-8"},
+		}, nil)).
+		CmpBody(body)
+
+	testAPI.Name("formats are cached apart").
+		Get("/fizzbuzz?str1=warm&limit=6&format=csv").
+		CmpStatus(http.StatusOK).
+		CmpBody("n,value\n1,1\n2,2\n3,warm\n4,4\n5,buzz\n6,warm\n")
+	td.Cmp(t, handlers.ExportFizzBuzzCache.Len(), 2)
+
+	testAPI.Name("pretty responses are not cached").
+		Get("/fizzbuzz?str1=warm&limit=6&pretty").
+		CmpStatus(http.StatusOK)
+	td.Cmp(t, handlers.ExportFizzBuzzCache.Len(), 2)
+
+	testAPI.Name("metrics").
+		Get("/mon/metrics").
+		CmpStatus(http.StatusOK).
+		CmpBody(td.All(
+			td.Contains(`cache_hits_total{cache="fizzbuzz"}`),
+			td.Contains(`cache_misses_total{cache="fizzbuzz"}`),
+			td.Contains(`cache_size_bytes{cache="fizzbuzz"}`),
+		))
+}
+
+func TestFizzBuzzCacheWarmer(t *testing.T) {
+	g := stats.NewGatherer()
+	l := cache.NewLRU("warmer_test", 1<<20)
+	w := handlers.NewFizzBuzzCacheWarmer(g, l, 2)
+
+	input := func(str1 string, limit int) handlers.FizzBuzzInput {
+		in := handlers.FizzBuzzInput{Str1: &str1, Limit: &limit}
+		in.SetDefault()
+		return in
+	}
+
+	w.Warm()
+	td.Cmp(t, l.Len(), 0, "nothing to warm without statistics")
+
+	warm := input("warm", 6)
+	w.Hit(warm)
+	w.Hit(warm)
+	w.Hit(input("tepid", 3))
+	td.Cmp(t, g.OrderedValues(), []stats.Count{
+		{Key: "FizzBuzzInput str1=warm str2=buzz int1=3 int2=5 limit=6", Hit: 2},
+		{Key: "FizzBuzzInput str1=tepid str2=buzz int1=3 int2=5 limit=3", Hit: 1},
+	})
+
+	w.Warm()
+	td.Cmp(t, l.Len(), 2)
+	body, ok := handlers.ExportCachedBody(l, warm)
+	td.CmpTrue(t, ok)
+	td.Cmp(t, body, `{"result":["1","2","warm","4","buzz","warm"]}`+"\n")
+
+	w.Warm()
+	td.Cmp(t, l.Len(), 2, "cached inputs are not computed again")
+
+	for i := 0; i < 10; i++ {
+		w.Hit(input(fmt.Sprintf("cold%d", i), 3))
+	}
+	td.Cmp(t, w.ExportInputs(), td.Lte(4), "only the most used inputs are remembered")
+
+	w.Hit(warm)
+	l.Reset()
+	w.Warm()
+	_, ok = handlers.ExportCachedBody(l, warm)
+	td.CmpTrue(t, ok, "most used input is still warmed")
+}
+
+func TestFizzBuzzCacheWarmerRun(t *testing.T) {
+	g := stats.NewGatherer()
+	l := cache.NewLRU("warmer_run_test", 1<<20)
+	w := handlers.NewFizzBuzzCacheWarmer(g, l, 2)
+
+	str1, limit := "warm", 3
+	in := handlers.FizzBuzzInput{Str1: &str1, Limit: &limit}
+	in.SetDefault()
+	w.Hit(in)
+
+	stop := w.Run(time.Hour)
+	defer stop()
+
+	ok := false
+	for i := 0; i < 100 && !ok; i++ {
+		time.Sleep(10 * time.Millisecond)
+		_, ok = handlers.ExportCachedBody(l, in)
+	}
+	td.CmpTrue(t, ok, "cache is warmed before the first interval")
+}
+
+func TestFizzBuzzCacheWarmerForget(t *testing.T) {
+	g := stats.NewGatherer()
+	l := cache.NewLRU("warmer_forget_test", 1<<20)
+	w := handlers.NewFizzBuzzCacheWarmer(g, l, 2)
+
+	input := func(str1 string) handlers.FizzBuzzInput {
+		limit := 3
+		in := handlers.FizzBuzzInput{Str1: &str1, Limit: &limit}
+		in.SetDefault()
+		return in
+	}
+
+	warm, tepid := input("warm"), input("tepid")
+	warmKey := "FizzBuzzInput str1=warm str2=buzz int1=3 int2=5 limit=3"
+	w.Hit(warm)
+	w.Hit(tepid)
+	w.Warm()
+	td.Cmp(t, l.Len(), 2)
+
+	w.Forget(warmKey)
+	td.Cmp(t, w.ExportInputs(), 1)
+	_, ok := handlers.ExportCachedBody(l, warm)
+	td.CmpFalse(t, ok, "forgotten input is evicted")
+	_, ok = handlers.ExportCachedBody(l, tepid)
+	td.CmpTrue(t, ok, "other inputs are kept")
+
+	g.Deny(warmKey)
+	w.Hit(warm)
+	w.Warm()
+	td.Cmp(t, w.ExportInputs(), 1, "denied input is not remembered")
+	_, ok = handlers.ExportCachedBody(l, warm)
+	td.CmpFalse(t, ok, "denied input is not warmed")
+
+	w.ForgetAll()
+	td.Cmp(t, w.ExportInputs(), 0)
+	td.Cmp(t, l.Len(), 0)
+}
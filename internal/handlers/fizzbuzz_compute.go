@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FizzBuzzComputer writes fizzbuzz sequences the way GET /fizzbuzz responds
+// with them, without any HTTP request, e.g. for the command line.
+//
+// Inputs are validated and checked against the same thresholds as the
+// HTTP routes, but they are not counted in stats.
+type FizzBuzzComputer struct {
+	validator echo.Validator
+	logger    echo.Logger
+}
+
+// NewFizzBuzzComputer will spawn a FizzBuzzComputer instance, validating
+// inputs with v.
+func NewFizzBuzzComputer(v echo.Validator, logger echo.Logger) *FizzBuzzComputer {
+	return &FizzBuzzComputer{validator: v, logger: logger}
+}
+
+// Compute writes to w the body GET /fizzbuzz would respond to in, accept
+// and acceptLanguage being its Accept and Accept-Language headers.
+//
+// Its errors are the ones GET /fizzbuzz would respond with.
+func (fc *FizzBuzzComputer) Compute(w io.Writer, in FizzBuzzInput, accept, acceptLanguage string) error {
+	err := in.validate(fc.validator, fc.logger, acceptLanguage)
+	if err != nil {
+		return err
+	}
+
+	mime, err := in.negotiate(fc.logger, accept)
+	if err != nil {
+		return err
+	}
+
+	rs, err := in.newRules(fc.logger)
+	if err != nil {
+		return err
+	}
+
+	if in.paginated() {
+		out, err := in.page(fc.logger, rs, mime)
+		if err != nil {
+			return err
+		}
+		return encode(w, mime, out)
+	}
+
+	if in.Shape == ShapePeriodic {
+		out, err := in.periodic(fc.logger, rs, mime)
+		if err != nil {
+			return err
+		}
+		return encode(w, mime, out)
+	}
+
+	count, err := in.checkCount(fc.logger, rs, mime)
+	if err != nil {
+		return err
+	}
+
+	if mime == MIMEApplicationNDJSON {
+		bw := bufio.NewWriter(w)
+		enc := json.NewEncoder(bw)
+		g := in.generator(rs, 0)
+		for term, ok := g.Next(); ok; term, ok = g.Next() {
+			if err := enc.Encode(term); err != nil {
+				return err
+			}
+		}
+		return bw.Flush()
+	}
+	return encode(w, mime, in.listOutput(rs, count, mime))
+}
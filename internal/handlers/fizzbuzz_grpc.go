@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/fizzbuzzpb"
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// FizzBuzzService serves the fizzbuzz gRPC service.
+//
+// Requests are validated and counted in stats the same way as the
+// /fizzbuzz HTTP routes, both transports sharing one ranking.
+type FizzBuzzService struct {
+	fizzbuzzpb.UnimplementedFizzBuzzServiceServer
+
+	validator echo.Validator
+	logger    echo.Logger
+}
+
+// NewFizzBuzzService will spawn a FizzBuzzService instance, validating
+// requests with v.
+func NewFizzBuzzService(v echo.Validator, logger echo.Logger) *FizzBuzzService {
+	return &FizzBuzzService{validator: v, logger: logger}
+}
+
+// Compute responds to FizzBuzzService.Compute gRPC requests.
+//
+// It behaves like FizzBuzz, FizzBuzzMaxLimit applying to the number of
+// returned terms and FizzBuzzGRPCMaxBytes to their estimated size.
+func (s *FizzBuzzService) Compute(ctx context.Context, req *fizzbuzzpb.FizzBuzzRequest) (*fizzbuzzpb.ComputeResponse, error) {
+	in, rs, err := s.input(ctx, req, FizzBuzzMaxLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	count := in.count()
+	size := responseSize(in, rs, 0, count, echo.MIMEApplicationProtobuf)
+	if err := checkBudget(s.logger, size, FizzBuzzGRPCMaxBytes); err != nil {
+		return nil, grpcError(err)
+	}
+
+	// inputs are valid, add this request to fizzbuzz's stats
+	go in.Register()
+
+	return &fizzbuzzpb.ComputeResponse{
+		Result: fizzBuzzOutput(in, rs, 0, count).Result,
+	}, nil
+}
+
+// Stream responds to FizzBuzzService.Stream gRPC requests.
+//
+// It sends up to FizzBuzzStreamMaxLimit terms, by chunks of
+// streamFlushSize terms, and stops as soon as the client disconnects.
+// Like streamed FizzBuzz responses, it has no byte budget.
+func (s *FizzBuzzService) Stream(req *fizzbuzzpb.FizzBuzzRequest, stream fizzbuzzpb.FizzBuzzService_StreamServer) error {
+	ctx := stream.Context()
+	in, rs, err := s.input(ctx, req, FizzBuzzStreamMaxLimit)
+	if err != nil {
+		return err
+	}
+
+	// inputs are valid, add this request to fizzbuzz's stats
+	go in.Register()
+
+	count := in.count()
+	g := in.generator(rs, 0)
+	chunk := make([]string, 0, streamFlushSize)
+	for i := uint64(0); i < count; i++ {
+		term, _ := g.Next()
+		chunk = append(chunk, term)
+		if len(chunk) < streamFlushSize && i+1 < count {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			s.logger.Infof("fizzbuzz stream interrupted after %d terms: %v", i, err)
+			return status.FromContextError(err).Err()
+		}
+		if err := stream.Send(&fizzbuzzpb.StreamResponse{Terms: chunk}); err != nil {
+			s.logger.Warnf("failed to stream fizzbuzz terms: %v", err)
+			return err
+		}
+		chunk = chunk[:0]
+	}
+	return nil
+}
+
+// Stats responds to FizzBuzzService.Stats gRPC requests.
+//
+// It behaves like FizzBuzzStats.
+func (s *FizzBuzzService) Stats(context.Context, *fizzbuzzpb.StatsRequest) (*fizzbuzzpb.StatsResponse, error) {
+	values := fizzBuzzGatherer.OrderedValues()
+	values = values[:min(len(values), 100)]
+
+	res := &fizzbuzzpb.StatsResponse{Counts: make([]*fizzbuzzpb.Count, len(values))}
+	for i, v := range values {
+		res.Counts[i] = &fizzbuzzpb.Count{Key: v.Key, Hit: int64(v.Hit)}
+	}
+	return res, nil
+}
+
+// input converts and validates a request, up to maxLimit terms.
+func (s *FizzBuzzService) input(ctx context.Context, req *fizzbuzzpb.FizzBuzzRequest, maxLimit int) (FizzBuzzInput, *fizzbuzz.Rules, error) {
+	in, err := fizzBuzzInputFromProto(req)
+	if err != nil {
+		s.logger.Warnf("failed to parse request: %v", err)
+		return in, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var acceptLanguage string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("accept-language"); len(values) > 0 {
+			acceptLanguage = values[0]
+		}
+	}
+
+	if err := in.validate(s.validator, s.logger, acceptLanguage); err != nil {
+		return in, nil, grpcError(err)
+	}
+
+	rs, err := fizzbuzz.NewRules(in.rules(), in.combination(), in.numbers)
+	if err != nil {
+		s.logger.Warnf("failed to build rules: %v", err)
+		return in, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	count := in.count()
+	if count > uint64(maxLimit) {
+		s.logger.Warnf("%d terms is higher than threshold %d", count, maxLimit)
+		return in, nil, status.Errorf(codes.InvalidArgument, "limit should be lower than %d", maxLimit)
+	}
+	return in, rs, nil
+}
+
+// fizzBuzzInputFromProto converts a gRPC request to the FizzBuzzInput the
+// HTTP routes bind.
+func fizzBuzzInputFromProto(req *fizzbuzzpb.FizzBuzzRequest) (FizzBuzzInput, error) {
+	in := FizzBuzzInput{
+		Str1:      req.Str1,
+		Str2:      req.Str2,
+		Int1:      intPtr(req.Int1),
+		Int2:      intPtr(req.Int2),
+		Step:      intPtr(req.Step),
+		Limit:     intPtr(req.Limit),
+		Combine:   req.Combine,
+		Separator: req.Separator,
+		Reverse:   req.Reverse,
+		Numbers:   req.Numbers,
+	}
+
+	for _, r := range req.Rules {
+		in.Rules = append(in.Rules, fizzbuzz.Rule{Kind: r.Kind, Arg: r.Arg, Divisor: int(r.Divisor), Word: r.Word})
+	}
+	for _, o := range req.Overrides {
+		in.Overrides = append(in.Overrides, fizzbuzz.Override{Divisor: int(o.Divisor), Word: o.Word})
+	}
+
+	var ok bool
+	if req.From != nil {
+		if in.From, ok = new(big.Int).SetString(*req.From, 10); !ok {
+			return in, fmt.Errorf("from %q should be an integer", *req.From)
+		}
+	}
+	if req.To != nil {
+		if in.To, ok = new(big.Int).SetString(*req.To, 10); !ok {
+			return in, fmt.Errorf("to %q should be an integer", *req.To)
+		}
+	}
+	return in, nil
+}
+
+func intPtr(v *int64) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+// grpcError turns a handler error into a gRPC status error.
+func grpcError(err error) error {
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	code := codes.Unknown
+	switch httpErr.Code {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusRequestEntityTooLarge:
+		code = codes.ResourceExhausted
+	case http.StatusInternalServerError:
+		code = codes.Internal
+	}
+	return status.Error(code, fmt.Sprint(httpErr.Message))
+}
@@ -0,0 +1,198 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/fizzbuzzpb"
+	"github.com/c-roussel/fizzbuzz-api/internal/handlers"
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"github.com/maxatome/go-testdeep/helpers/tdhttp"
+	"github.com/maxatome/go-testdeep/td"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// newGRPCClient serves the gRPC server in memory.
+func newGRPCClient(t *testing.T) fizzbuzzpb.FizzBuzzServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	s := server.NewGRPC()
+	go s.Serve(lis) //nolint:errcheck
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	td.Require(t).CmpNoError(err)
+	t.Cleanup(func() { conn.Close() })
+
+	return fizzbuzzpb.NewFizzBuzzServiceClient(conn)
+}
+
+func TestFizzBuzzGRPCCompute(t *testing.T) {
+	client := newGRPCClient(t)
+
+	testCases := []struct {
+		name           string
+		req            *fizzbuzzpb.FizzBuzzRequest
+		md             metadata.MD
+		expectedCode   codes.Code
+		expectedResult []string
+		expectedError  string
+	}{
+		{
+			name:           "default values",
+			req:            &fizzbuzzpb.FizzBuzzRequest{Limit: proto.Int64(5)},
+			expectedCode:   codes.OK,
+			expectedResult: []string{"1", "2", "fizz", "4", "buzz"},
+		},
+		{
+			name: "rules",
+			req: &fizzbuzzpb.FizzBuzzRequest{
+				Rules: []*fizzbuzzpb.Rule{{Divisor: 2, Word: "le"}, {Kind: "ends_with", Arg: "7", Word: "seven"}},
+				From:  proto.String("9223372036854775806"),
+				To:    proto.String("9223372036854775808"),
+			},
+			expectedCode:   codes.OK,
+			expectedResult: []string{"le", "seven", "le"},
+		},
+		{
+			name: "words",
+			req: &fizzbuzzpb.FizzBuzzRequest{
+				Limit:   proto.Int64(2),
+				Numbers: "words",
+			},
+			md:             metadata.Pairs("accept-language", "fr-FR"),
+			expectedCode:   codes.OK,
+			expectedResult: []string{"un", "deux"},
+		},
+		{
+			name:          "invalid limit",
+			req:           &fizzbuzzpb.FizzBuzzRequest{Limit: proto.Int64(-1)},
+			expectedCode:  codes.InvalidArgument,
+			expectedError: "limit should be at least 0",
+		},
+		{
+			name: "rules along with shorthand",
+			req: &fizzbuzzpb.FizzBuzzRequest{
+				Int1:  proto.Int64(2),
+				Rules: []*fizzbuzzpb.Rule{{Divisor: 2, Word: "le"}},
+			},
+			expectedCode:  codes.InvalidArgument,
+			expectedError: "rule cannot be used along with int1, int2, str1 or str2",
+		},
+		{
+			name:          "invalid from",
+			req:           &fizzbuzzpb.FizzBuzzRequest{From: proto.String("one")},
+			expectedCode:  codes.InvalidArgument,
+			expectedError: `from "one" should be an integer`,
+		},
+		{
+			name:          "too many terms",
+			req:           &fizzbuzzpb.FizzBuzzRequest{Limit: proto.Int64(10001)},
+			expectedCode:  codes.InvalidArgument,
+			expectedError: "limit should be lower than 10000",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := metadata.NewOutgoingContext(context.Background(), tc.md)
+			res, err := client.Compute(ctx, tc.req)
+
+			st, _ := status.FromError(err)
+			td.Cmp(t, st.Code(), tc.expectedCode)
+			td.Cmp(t, st.Message(), tc.expectedError)
+			td.Cmp(t, res.GetResult(), tc.expectedResult)
+		})
+	}
+}
+
+func TestFizzBuzzGRPCBudget(t *testing.T) {
+	defer func(bytes int) { handlers.FizzBuzzGRPCMaxBytes = bytes }(handlers.FizzBuzzGRPCMaxBytes)
+	handlers.FizzBuzzGRPCMaxBytes = 1000
+
+	client := newGRPCClient(t)
+
+	res, err := client.Compute(context.Background(), &fizzbuzzpb.FizzBuzzRequest{Limit: proto.Int64(50)})
+	td.Require(t).CmpNoError(err)
+	td.Cmp(t, len(res.Result), 50)
+
+	_, err = client.Compute(context.Background(), &fizzbuzzpb.FizzBuzzRequest{Limit: proto.Int64(1000)})
+	st, _ := status.FromError(err)
+	td.Cmp(t, st.Code(), codes.ResourceExhausted)
+	td.Cmp(t, st.Message(), "response would weigh about 12140 bytes, above the 1000 bytes budget: lower limit, page_size or words length")
+
+	// streams have no byte budget
+	stream, err := client.Stream(context.Background(), &fizzbuzzpb.FizzBuzzRequest{Limit: proto.Int64(1000)})
+	td.Require(t).CmpNoError(err)
+	var terms int
+	for {
+		res, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		td.Require(t).CmpNoError(err)
+		terms += len(res.Terms)
+	}
+	td.Cmp(t, terms, 1000)
+}
+
+func TestFizzBuzzGRPCStream(t *testing.T) {
+	client := newGRPCClient(t)
+
+	stream, err := client.Stream(context.Background(), &fizzbuzzpb.FizzBuzzRequest{Limit: proto.Int64(2500)})
+	td.Require(t).CmpNoError(err)
+
+	var sizes []int
+	var terms []string
+	for {
+		res, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		td.Require(t).CmpNoError(err)
+		sizes = append(sizes, len(res.Terms))
+		terms = append(terms, res.Terms...)
+	}
+
+	td.Cmp(t, sizes, []int{1024, 1024, 452})
+	td.Cmp(t, terms[:5], []string{"1", "2", "fizz", "4", "buzz"})
+	td.Cmp(t, terms[2499], "buzz")
+
+	stream, err = client.Stream(context.Background(), &fizzbuzzpb.FizzBuzzRequest{Step: proto.Int64(0)})
+	td.Require(t).CmpNoError(err)
+	_, err = stream.Recv()
+	td.Cmp(t, status.Code(err), codes.InvalidArgument)
+}
+
+func TestFizzBuzzGRPCStats(t *testing.T) {
+	client := newGRPCClient(t)
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	_, err := client.Compute(context.Background(), &fizzbuzzpb.FizzBuzzRequest{Limit: proto.Int64(8)})
+	td.Require(t).CmpNoError(err)
+
+	testAPI.Get("/fizzbuzz?limit=8").
+		CmpStatus(http.StatusOK)
+
+	// gathering is done asynchronously
+	time.Sleep(100 * time.Millisecond)
+
+	res, err := client.Stats(context.Background(), &fizzbuzzpb.StatsRequest{})
+	td.Require(t).CmpNoError(err)
+	td.Cmp(t, res.Counts, td.Contains(td.Struct(&fizzbuzzpb.Count{
+		Key: "FizzBuzzInput str1=fizz str2=buzz int1=3 int2=5 limit=8",
+		Hit: 2,
+	}, nil)))
+}
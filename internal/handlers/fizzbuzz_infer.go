@@ -0,0 +1,369 @@
+package handlers
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
+	"github.com/labstack/echo/v4"
+)
+
+// FizzBuzzInferInput describes the expected input for the fizzbuzz infer
+// handler.
+//
+// Sequence holds consecutive fizzbuzz terms, the first one being From's
+// term.
+type FizzBuzzInferInput struct {
+	Sequence []string `json:"sequence" validate:"required,min=1"`
+	From     *int     `json:"from" validate:"omitempty,min=-1000000000,max=1000000000"`
+}
+
+// FizzBuzzInferOutput describes the response output for the fizzbuzz infer
+// handler.
+//
+// Reason explains why no candidate reproduces the sequence, it is only set
+// when Candidates is empty.
+type FizzBuzzInferOutput struct {
+	Candidates []FizzBuzzInput `json:"candidates"`
+	Reason     string          `json:"reason,omitempty"`
+}
+
+// FizzBuzzInfer responds to POST /fizbuzz/infer HTTP requests.
+//
+// It will respond with a 200 HTTP repsonse embedding
+// a FizzBuzzInferOutput result.
+//
+// Candidates are the int1/int2/str1/str2 inputs reproducing the sequence,
+// with the smallest possible int1 and int2. Words which never appear in
+// the sequence are left out, str1 being the first one to appear unless
+// str1+str2 tells otherwise. Every candidate is checked against the terms
+// FizzBuzz computes.
+//
+// @Summary Infer fizzbuzz parameters.
+// @Description Find the parameters of a fizzbuzz sequence.
+// @Tags fizzbuzz
+// @Accept json
+// @Param input body handlers.FizzBuzzInferInput true "fizzbuzz's sequence"
+// @Produce json
+// @Success 200 {object} handlers.FizzBuzzInferOutput
+// @Failure 400 {object} handlers.Problem
+// @Router /fizzbuzz/infer [post]
+func FizzBuzzInfer(c echo.Context) error {
+	var in FizzBuzzInferInput
+	err := c.Bind(&in)
+	if err != nil {
+		c.Logger().Warnf("failed to parse body: %v", err)
+		return err
+	}
+
+	err = c.Validate(&in)
+	if err != nil {
+		c.Logger().Warnf("failed to validate body: %v", err)
+		return err
+	}
+
+	if len(in.Sequence) > FizzBuzzMaxLimit {
+		c.Logger().Warnf("%d terms is higher than threshold %d", len(in.Sequence), FizzBuzzMaxLimit)
+		threshold := strconv.Itoa(FizzBuzzMaxLimit)
+		return invalidParams(newInvalidParam("sequence", strconv.Itoa(len(in.Sequence)), "max="+threshold, "max-items", threshold))
+	}
+
+	from := int(defaultFizzBuzzInput.From.Int64())
+	if in.From != nil {
+		from = *in.From
+	}
+
+	return c.JSON(http.StatusOK, infer(in.Sequence, from))
+}
+
+// Word roles in an inferred sequence, as a bitmask.
+const (
+	roleStr1 = 1 << iota
+	roleStr2
+
+	roleBoth = roleStr1 | roleStr2
+)
+
+// infer finds the minimal inputs reproducing sequence, its first term being
+// from's one.
+func infer(sequence []string, from int) FizzBuzzInferOutput {
+	out := FizzBuzzInferOutput{Candidates: []FizzBuzzInput{}}
+
+	// words are the terms which are not their own number, in order of
+	// appearance
+	var words []string
+	for i, term := range sequence {
+		if term != strconv.Itoa(from+i) && !contains(words, term) {
+			words = append(words, term)
+		}
+	}
+
+	if len(words) > 3 {
+		out.Reason = fmt.Sprintf(
+			"%d distinct words found, at most str1, str2 and str1+str2 are expected",
+			len(words),
+		)
+		return out
+	}
+
+	r := inferRange{from: from, to: from + len(sequence) - 1}
+	reasons := []string{}
+	for _, roles := range wordRoles(len(words)) {
+		pairs := roleStrings(words, roles)
+		if len(pairs) == 0 {
+			continue
+		}
+
+		// positions of each role's values
+		var positions [2][]int
+		for i, term := range sequence {
+			for j, w := range words {
+				if term != w {
+					continue
+				}
+				if roles[j]&roleStr1 != 0 {
+					positions[0] = append(positions[0], from+i)
+				}
+				if roles[j]&roleStr2 != 0 {
+					positions[1] = append(positions[1], from+i)
+				}
+			}
+		}
+
+		int1, ok1 := r.minimalDivisor(positions[0])
+		int2, ok2 := r.minimalDivisor(positions[1])
+		if !ok1 || !ok2 {
+			reasons = append(reasons, inferFailure(words, roles, ok1))
+			continue
+		}
+
+		for _, pair := range pairs {
+			candidate := FizzBuzzInput{
+				Str1: pair[0],
+				Str2: pair[1],
+				Int1: &int1,
+				Int2: &int2,
+				From: big.NewInt(int64(r.from)),
+				To:   big.NewInt(int64(r.to)),
+			}
+			if reproduces(candidate, sequence) {
+				out.Candidates = append(out.Candidates, candidate)
+			}
+		}
+	}
+
+	if len(out.Candidates) == 0 {
+		switch {
+		case len(reasons) > 0:
+			out.Reason = reasons[0]
+		default:
+			out.Reason = fmt.Sprintf(
+				"words %s cannot be str1, str2 and str1+str2",
+				strings.Join(quoteAll(words), ", "),
+			)
+		}
+	}
+	return out
+}
+
+// wordRoles lists the ways to assign distinct roles to n words.
+//
+// When str1+str2 does not appear, str1 and str2 are interchangeable: str1
+// is then the first word to appear.
+func wordRoles(n int) [][]int {
+	all := []int{roleStr1, roleStr2, roleBoth}
+
+	res := [][]int{{}}
+	for i := 0; i < n; i++ {
+		var next [][]int
+		for _, prefix := range res {
+			for _, role := range all {
+				if containsRole(prefix, role) {
+					continue
+				}
+				next = append(next, append(append([]int{}, prefix...), role))
+			}
+		}
+		res = next
+	}
+
+	var filtered [][]int
+	for _, roles := range res {
+		if !containsRole(roles, roleBoth) && len(roles) > 0 && roles[0] != roleStr1 {
+			continue
+		}
+		filtered = append(filtered, roles)
+	}
+	return filtered
+}
+
+// roleStrings deduces the str1 and str2 pairs matching the words roles.
+// They are nil when they do not appear in the sequence.
+//
+// It returns no pair when words do not match their roles, and every split
+// of str1+str2 when it is the only word.
+func roleStrings(words []string, roles []int) [][2]*string {
+	var str1, str2, both *string
+	for i, role := range roles {
+		w := words[i]
+		switch role {
+		case roleStr1:
+			str1 = &w
+		case roleStr2:
+			str2 = &w
+		case roleBoth:
+			both = &w
+		}
+	}
+
+	switch {
+	case both == nil:
+		return [][2]*string{{str1, str2}}
+	case str1 != nil && str2 != nil:
+		if *both != *str1+*str2 {
+			return nil
+		}
+		return [][2]*string{{str1, str2}}
+	case str1 != nil:
+		if !strings.HasPrefix(*both, *str1) {
+			return nil
+		}
+		rest := strings.TrimPrefix(*both, *str1)
+		return [][2]*string{{str1, &rest}}
+	case str2 != nil:
+		if !strings.HasSuffix(*both, *str2) {
+			return nil
+		}
+		rest := strings.TrimSuffix(*both, *str2)
+		return [][2]*string{{&rest, str2}}
+	}
+
+	var pairs [][2]*string
+	for i := 1; i < len(*both); i++ {
+		head, tail := (*both)[:i], (*both)[i:]
+		pairs = append(pairs, [2]*string{&head, &tail})
+	}
+	return pairs
+}
+
+// inferFailure explains why no divisor reproduces the words roles.
+func inferFailure(words []string, roles []int, str1Found bool) string {
+	role := roleStr2
+	if !str1Found {
+		role = roleStr1
+	}
+
+	var matching []string
+	for i, r := range roles {
+		if r&role != 0 {
+			matching = append(matching, strconv.Quote(words[i]))
+		}
+	}
+	if len(matching) == 0 {
+		return "0 is a multiple of every divisor, it cannot be a number"
+	}
+	return fmt.Sprintf("no divisor matches exactly the positions of %s", strings.Join(matching, " and "))
+}
+
+// reproduces tells whether FizzBuzz computes sequence from the candidate.
+func reproduces(candidate FizzBuzzInput, sequence []string) bool {
+	candidate.SetDefault()
+	rs, err := fizzbuzz.NewRules(candidate.rules(), candidate.combination(), candidate.numbers)
+	if err != nil {
+		return false
+	}
+
+	out := fizzBuzzOutput(candidate, rs, 0, candidate.count())
+	if len(out.Result) != len(sequence) {
+		return false
+	}
+	for i, term := range out.Result {
+		if term != sequence[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// inferRange describes the consecutive numbers of an inferred sequence.
+type inferRange struct {
+	from, to int
+}
+
+// multiples counts the range's multiples of d.
+func (r inferRange) multiples(d int) int {
+	return floorDiv(r.to, d) - floorDiv(r.from-1, d)
+}
+
+// minimalDivisor returns the smallest divisor whose multiples in the range
+// are exactly values.
+func (r inferRange) minimalDivisor(values []int) (int, bool) {
+	g := 0
+	for _, v := range values {
+		if v < 0 {
+			v = -v
+		}
+		g = fizzbuzz.GCD(g, v)
+	}
+
+	if g == 0 {
+		// any divisor is a candidate, the range's bounds not being
+		// multiples of numbers beyond them
+		limit := r.to
+		if -r.from > limit {
+			limit = -r.from
+		}
+		for d := 1; d <= limit+1; d++ {
+			if r.multiples(d) == len(values) {
+				return d, true
+			}
+		}
+		return 0, false
+	}
+
+	var large []int
+	for d := 1; d*d <= g; d++ {
+		if g%d != 0 {
+			continue
+		}
+		if r.multiples(d) == len(values) {
+			return d, true
+		}
+		large = append(large, g/d)
+	}
+	for i := len(large) - 1; i >= 0; i-- {
+		if r.multiples(large[i]) == len(values) {
+			return large[i], true
+		}
+	}
+	return 0, false
+}
+
+// floorDiv divides a by the positive d, rounding towards negative infinity.
+func floorDiv(a, d int) int {
+	q := a / d
+	if a%d != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+func containsRole(roles []int, role int) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return quoted
+}
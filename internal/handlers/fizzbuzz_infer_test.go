@@ -0,0 +1,116 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"github.com/maxatome/go-testdeep/helpers/tdhttp"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestFizzBuzzInfer(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	candidate := func(int1, int2 int, str1, str2 interface{}, from, to int) interface{} {
+		return td.SuperMapOf(map[string]interface{}{
+			"int1": int1,
+			"int2": int2,
+			"str1": str1,
+			"str2": str2,
+			"from": from,
+			"to":   to,
+		}, nil)
+	}
+
+	testCases := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expected       interface{}
+	}{
+		{
+			name:           "every word",
+			body:           `{"sequence": ["1", "le", "boncoin", "le", "5", "leboncoin"]}`,
+			expectedStatus: http.StatusOK,
+			expected: td.JSON(`{"candidates": $1}`, []interface{}{
+				candidate(2, 3, "le", "boncoin", 1, 6),
+			}),
+		},
+		{
+			name:           "str2 before str1",
+			body:           `{"sequence": ["buzz", "11", "fizz", "13", "14", "fizzbuzz"], "from": 10}`,
+			expectedStatus: http.StatusOK,
+			expected: td.JSON(`{"candidates": $1}`, []interface{}{
+				candidate(3, 5, "fizz", "buzz", 10, 15),
+			}),
+		},
+		{
+			name:           "str2 never appears",
+			body:           `{"sequence": ["1", "2", "fizz", "4"]}`,
+			expectedStatus: http.StatusOK,
+			expected: td.JSON(`{"candidates": $1}`, []interface{}{
+				candidate(3, 5, "fizz", nil, 1, 4),
+				candidate(3, 3, "f", "izz", 1, 4),
+				candidate(3, 3, "fi", "zz", 1, 4),
+				candidate(3, 3, "fiz", "z", 1, 4),
+			}),
+		},
+		{
+			name:           "only str1+str2 appears",
+			body:           `{"sequence": ["1", "2", "ab", "4", "5", "ab"]}`,
+			expectedStatus: http.StatusOK,
+			expected: td.JSON(`{"candidates": $1}`, []interface{}{
+				candidate(3, 7, "ab", nil, 1, 6),
+				candidate(3, 3, "a", "b", 1, 6),
+			}),
+		},
+		{
+			name:           "too many words",
+			body:           `{"sequence": ["a", "b", "c", "d"]}`,
+			expectedStatus: http.StatusOK,
+			expected: td.JSON(`{
+				"candidates": [],
+				"reason": "4 distinct words found, at most str1, str2 and str1+str2 are expected"
+			}`),
+		},
+		{
+			name:           "inconsistent positions",
+			body:           `{"sequence": ["1", "fizz", "fizz"]}`,
+			expectedStatus: http.StatusOK,
+			expected: td.JSON(`{
+				"candidates": [],
+				"reason": "no divisor matches exactly the positions of \"fizz\""
+			}`),
+		},
+		{
+			name:           "inconsistent words",
+			body:           `{"sequence": ["1", "fizz", "buzz", "fizz", "5", "bazz"]}`,
+			expectedStatus: http.StatusOK,
+			expected: td.JSON(`{
+				"candidates": [],
+				"reason": "words \"fizz\", \"buzz\", \"bazz\" cannot be str1, str2 and str1+str2"
+			}`),
+		},
+		{
+			name:           "empty sequence",
+			body:           `{"sequence": []}`,
+			expectedStatus: http.StatusBadRequest,
+			expected:       td.JSON(`SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "sequence", "value": "[]", "constraint": "min=1", "reason": "sequence should hold at least 1 items"}]})`),
+		},
+		{
+			name:           "too long sequence",
+			body:           `{"sequence": [` + strings.Repeat(`"1", `, 10000) + `"1"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expected:       td.JSON(`SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "sequence", "value": "10001", "constraint": "max=10000", "reason": "sequence should hold at most 10000 items"}]})`),
+		},
+	}
+	for _, tc := range testCases {
+		testAPI.Run(tc.name, func(ta *tdhttp.TestAPI) {
+			ta.Post("/fizzbuzz/infer", strings.NewReader(tc.body), "Content-Type", "application/json").
+				CmpStatus(tc.expectedStatus).
+				CmpJSONBody(tc.expected)
+		})
+	}
+}
@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+// FizzBuzzEnvLiveRate is the environment variable to override the servers
+// maximum rate on GET /fizzbuzz/live route.
+const FizzBuzzEnvLiveRate = "FIZZBUZZ_LIVE_MAX_RATE"
+
+// FizzBuzzLiveMaxRate is the maximum number of terms per second emitted by
+// a GET /fizzbuzz/live request.
+var FizzBuzzLiveMaxRate = 100
+
+// FizzBuzzLiveMinRate is the minimum number of terms per second emitted by
+// a GET /fizzbuzz/live request, bounding the interval between two terms.
+const FizzBuzzLiveMinRate = 0.001
+
+// MIMETextEventStream is the server-sent events MIME type.
+const MIMETextEventStream = "text/event-stream"
+
+// HeaderLastEventID is the header sent by server-sent events clients when
+// reconnecting.
+const HeaderLastEventID = "Last-Event-ID"
+
+// Live stream end reasons, sent as the data of the end event, or as the
+// reason of the WebSocket close frame.
+const (
+	liveEndCompleted = "completed"
+	liveEndShutdown  = "shutdown"
+)
+
+// Live stream commands, sent by WebSocket clients as text messages.
+const (
+	liveCommandPause  = "pause"
+	liveCommandResume = "resume"
+)
+
+// liveWriteTimeout bounds the time spent writing a single live message.
+const liveWriteTimeout = 10 * time.Second
+
+func init() {
+	loadEnvInt(FizzBuzzEnvLiveRate, &FizzBuzzLiveMaxRate)
+}
+
+// FizzBuzzLive serves the live fizzbuzz streams, ending them all when its
+// server shuts down.
+type FizzBuzzLive struct {
+	upgrader websocket.Upgrader
+
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+// NewFizzBuzzLive will spawn a FizzBuzzLive instance, Shutdown having to be
+// called along with its server's shutdown.
+func NewFizzBuzzLive() *FizzBuzzLive {
+	return &FizzBuzzLive{done: make(chan struct{})}
+}
+
+// Shutdown ends the ongoing and future live streams.
+func (l *FizzBuzzLive) Shutdown() {
+	l.doneOnce.Do(func() { close(l.done) })
+}
+
+// liveEmitter writes the messages of a live stream.
+type liveEmitter interface {
+	term(i uint64, out FizzBuzzTermOutput) error
+	end(reason string) error
+}
+
+// Handle responds to GET /fizbuzz/live HTTP requests.
+//
+// It will emit the terms one by one, as FizzBuzzTermOutput, at `rate`
+// terms per second, from FizzBuzzLiveMinRate up to FizzBuzzLiveMaxRate. Terms are computed with the
+// same rules as FizzBuzz, FizzBuzzMaxLimit not applying: the range may be
+// as long as the client wants to listen.
+//
+// Terms are sent as server-sent `term` events, their id being the term's
+// index, followed by an `end` event once the range is completed or the
+// server shuts down. Clients reconnecting with a Last-Event-ID header
+// resume after the given index.
+//
+// When requested with a WebSocket upgrade, terms are sent as JSON text
+// messages instead, and the client may send `pause` and `resume` text
+// messages. The server closes the socket once the range is completed or
+// when it shuts down, the close reason telling which.
+//
+// The stream stops as soon as the client disconnects.
+//
+// @Summary Customizable fizzbuzz algorithm, live.
+// @Description Get your own version of the fizzbuzz algortihm, term by term.
+// @Tags fizzbuzz
+// @Accept */*
+// @Param int1  query int    false "fizzbuzz's first multiple"     minimum(1) default(3)
+// @Param int2  query int    false "fizzbuzz's second multiple"    minimum(1) default(5)
+// @Param str1  query string false "fizzbuzz's first replacement"             default(fizz)
+// @Param str2  query string false "fizzbuzz's second replacement"            default(buzz)
+// @Param rule  query []string false "fizzbuzz's rules, as divisor:word or kind:arg:word" collectionFormat(multi)
+// @Param limit query int    false "fizzbuzz's up-to value"        minimum(0) default(100)
+// @Param from  query int    false "fizzbuzz's starting value"                default(1)
+// @Param to    query int    false "fizzbuzz's up-to value, replaces limit"
+// @Param step  query int    false "fizzbuzz's increment"          minimum(1) default(1)
+// @Param combine query string false "fizzbuzz's combination of several matching words" Enums(concat, first, override) default(concat)
+// @Param separator query string false "fizzbuzz's separator of concatenated words"
+// @Param reverse query bool false "fizzbuzz's combination from the last matching word" default(false)
+// @Param override query []string false "fizzbuzz's combination overrides, as divisor:word" collectionFormat(multi)
+// @Param numbers query string false "fizzbuzz's numbers rendering, as decimal, hex, binary, base:N, roman, padded:N or words" default(decimal)
+// @Param rate  query number false "emitted terms per second"     minimum(0.001) default(1)
+// @Param Last-Event-ID header int false "index of the last received term"
+// @Produce text/event-stream
+// @Success 200 {object} handlers.FizzBuzzTermOutput "term events"
+// @Success 101 "Switching Protocols"
+// @Failure 400 {object} handlers.Problem
+// @Router /fizzbuzz/live [get]
+func (l *FizzBuzzLive) Handle(c echo.Context) error {
+	var in FizzBuzzInput
+	err := c.Bind(&in)
+	if err != nil {
+		c.Logger().Warnf("failed to parse query parameters: %v", err)
+		return err
+	}
+
+	if p, ok := in.excludedParam("excluded_with=live", "live-exclusive"); ok {
+		c.Logger().Warn("live requested with stream, format, pagination or periodic shape")
+		return invalidParams(p)
+	}
+
+	err = validateFizzBuzzInput(c, &in)
+	if err != nil {
+		return err
+	}
+
+	rate := 1.0
+	if str := c.QueryParam("rate"); str != "" {
+		rate, err = strconv.ParseFloat(str, 64)
+		if err != nil || !(rate >= FizzBuzzLiveMinRate && rate <= float64(FizzBuzzLiveMaxRate)) {
+			c.Logger().Warnf("invalid rate %q", str)
+			lower := strconv.FormatFloat(FizzBuzzLiveMinRate, 'f', -1, 64)
+			upper := strconv.Itoa(FizzBuzzLiveMaxRate)
+			return invalidParams(newInvalidParam("rate", str, "min="+lower+",max="+upper, "between", lower, upper))
+		}
+	}
+
+	var offset uint64
+	if str := c.Request().Header.Get(HeaderLastEventID); str != "" {
+		offset, err = strconv.ParseUint(str, 10, 64)
+		if err != nil || offset == ^uint64(0) {
+			c.Logger().Warnf("invalid last event id %q", str)
+			return invalidParams(newInvalidParam(HeaderLastEventID, str, "term-index", "term-index"))
+		}
+		offset++
+	}
+
+	rs, err := in.newRules(c.Logger())
+	if err != nil {
+		return err
+	}
+
+	// inputs are valid, add this request to fizzbuzz's stats
+	go in.Register()
+
+	interval := time.Duration(float64(time.Second) / rate)
+	if websocket.IsWebSocketUpgrade(c.Request()) {
+		return l.serveWebSocket(c, in, rs, interval)
+	}
+	return l.serveEvents(c, in, rs, offset, interval)
+}
+
+// excludedParam describes the first stream, format, pagination or shape
+// parameter of the input, if any, for routes which cannot use them.
+func (in FizzBuzzInput) excludedParam(constraint, key string) (InvalidParam, bool) {
+	switch {
+	case in.Stream:
+		return newInvalidParam("stream", "true", constraint, key), true
+	case in.Format != "":
+		return newInvalidParam("format", in.Format, constraint, key), true
+	case in.paginated():
+		return in.paginationParam(constraint, key), true
+	case in.Shape == ShapePeriodic:
+		return newInvalidParam("shape", in.Shape, constraint, key), true
+	}
+	return InvalidParam{}, false
+}
+
+// serveEvents emits the terms as server-sent events, starting from the
+// offset-th one.
+func (l *FizzBuzzLive) serveEvents(c echo.Context, in FizzBuzzInput, rs *fizzbuzz.Rules, offset uint64, interval time.Duration) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, MIMETextEventStream)
+	res.Header().Set(HeaderCacheControl, cacheControlRevalidate)
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	l.run(c.Request().Context(), c, &eventEmitter{res: res}, in, rs, offset, interval, nil)
+	return nil
+}
+
+// serveWebSocket upgrades the connection and emits the terms as WebSocket
+// text messages, reading the pause and resume commands.
+func (l *FizzBuzzLive) serveWebSocket(c echo.Context, in FizzBuzzInput, rs *fizzbuzz.Rules, interval time.Duration) error {
+	conn, err := l.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		// the upgrader already responded with an HTTP error
+		c.Logger().Warnf("failed to upgrade to websocket: %v", err)
+		return nil
+	}
+	defer conn.Close()
+
+	// a hijacked connection's request context is not canceled on
+	// disconnection, reading is the only way to notice it
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	commands := make(chan string)
+	go func() {
+		defer cancel()
+		for {
+			kind, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if kind != websocket.TextMessage {
+				continue
+			}
+
+			select {
+			case commands <- string(msg):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	l.run(ctx, c, &socketEmitter{conn: conn}, in, rs, 0, interval, commands)
+	return nil
+}
+
+// run emits a term every interval, starting from the offset-th one, until
+// the range is completed, the client disconnects or the server shuts down.
+func (l *FizzBuzzLive) run(ctx context.Context, c echo.Context, em liveEmitter, in FizzBuzzInput, rs *fizzbuzz.Rules,
+	offset uint64, interval time.Duration, commands <-chan string) {
+	count := in.count()
+	n := new(big.Int).SetUint64(offset)
+	n.Mul(n, big.NewInt(int64(*in.Step))).Add(n, in.From)
+	step := big.NewInt(int64(*in.Step))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	paused := false
+	reason := liveEndCompleted
+	for i := offset; i < count; {
+		select {
+		case <-ctx.Done():
+			c.Logger().Infof("fizzbuzz live interrupted after %d terms: %v", i, ctx.Err())
+			return
+		case <-l.done:
+			reason = liveEndShutdown
+			i = count
+		case cmd := <-commands:
+			switch cmd {
+			case liveCommandPause:
+				paused = true
+			case liveCommandResume:
+				paused = false
+			default:
+				c.Logger().Warnf("unknown fizzbuzz live command %q", cmd)
+			}
+		case <-ticker.C:
+			if paused {
+				continue
+			}
+			if err := em.term(i, fizzBuzzTerm(in, rs, n)); err != nil {
+				c.Logger().Warnf("failed to emit fizzbuzz term: %v", err)
+				return
+			}
+			n.Add(n, step)
+			i++
+		}
+	}
+
+	if err := em.end(reason); err != nil {
+		c.Logger().Warnf("failed to end fizzbuzz live: %v", err)
+	}
+}
+
+// eventEmitter writes server-sent events.
+type eventEmitter struct {
+	res *echo.Response
+}
+
+func (em *eventEmitter) term(i uint64, out FizzBuzzTermOutput) error {
+	data, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	return em.write(fmt.Sprintf("id: %d\nevent: term\ndata: %s\n\n", i, data))
+}
+
+func (em *eventEmitter) end(reason string) error {
+	return em.write(fmt.Sprintf("event: end\ndata: %q\n\n", reason))
+}
+
+func (em *eventEmitter) write(event string) error {
+	if _, err := em.res.Write([]byte(event)); err != nil {
+		return err
+	}
+	em.res.Flush()
+	return nil
+}
+
+// socketEmitter writes WebSocket messages.
+type socketEmitter struct {
+	conn *websocket.Conn
+}
+
+func (em *socketEmitter) term(_ uint64, out FizzBuzzTermOutput) error {
+	if err := em.conn.SetWriteDeadline(time.Now().Add(liveWriteTimeout)); err != nil {
+		return err
+	}
+	return em.conn.WriteJSON(out)
+}
+
+func (em *socketEmitter) end(reason string) error {
+	code := websocket.CloseNormalClosure
+	if reason == liveEndShutdown {
+		code = websocket.CloseGoingAway
+	}
+	return em.conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(liveWriteTimeout),
+	)
+}
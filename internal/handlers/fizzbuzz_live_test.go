@@ -0,0 +1,203 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/handlers"
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"github.com/gorilla/websocket"
+	"github.com/maxatome/go-testdeep/helpers/tdhttp"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestFizzBuzzLiveEvents(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testAPI.Name("events").
+		Get("/fizzbuzz/live?limit=3&rate=100").
+		CmpStatus(http.StatusOK).
+		CmpHeader(td.SuperMapOf(http.Header{
+			"Content-Type":  {handlers.MIMETextEventStream},
+			"Cache-Control": {"no-cache"},
+		}, nil)).
+		CmpBody("id: 0\nevent: term\ndata: {\"n\":1,\"value\":\"1\",\"matched\":[]}\n\n" +
+			"id: 1\nevent: term\ndata: {\"n\":2,\"value\":\"2\",\"matched\":[]}\n\n" +
+			"id: 2\nevent: term\ndata: {\"n\":3,\"value\":\"fizz\",\"matched\":[\"int1\"]}\n\n" +
+			"event: end\ndata: \"completed\"\n\n")
+
+	testAPI.Name("resumed events").
+		Get("/fizzbuzz/live?from=10&step=5&limit=20&rate=100", "Last-Event-ID", "1").
+		CmpStatus(http.StatusOK).
+		CmpBody("id: 2\nevent: term\ndata: {\"n\":20,\"value\":\"buzz\",\"matched\":[\"int2\"]}\n\n" +
+			"event: end\ndata: \"completed\"\n\n")
+
+	testCases := []struct {
+		name        string
+		query       string
+		headers     []interface{}
+		problemType string
+		expected    string
+	}{
+		{
+			name:        "zero rate",
+			query:       "rate=0",
+			problemType: handlers.ProblemTypeInvalidParams,
+			expected:    "rate should be between 0.001 and 100",
+		},
+		{
+			name:        "too high rate",
+			query:       "rate=101",
+			problemType: handlers.ProblemTypeInvalidParams,
+			expected:    "rate should be between 0.001 and 100",
+		},
+		{
+			name:        "tiny rate",
+			query:       "rate=1e-10",
+			problemType: handlers.ProblemTypeInvalidParams,
+			expected:    "rate should be between 0.001 and 100",
+		},
+		{
+			name:        "invalid rate",
+			query:       "rate=NaN",
+			problemType: handlers.ProblemTypeInvalidParams,
+			expected:    "rate should be between 0.001 and 100",
+		},
+		{
+			name:        "stream",
+			query:       "stream=true",
+			problemType: handlers.ProblemTypeInvalidParams,
+			expected:    "live cannot be used along with stream, format, cursor, page_size or shape=periodic",
+		},
+		{
+			name:        "invalid limit",
+			query:       "limit=-1",
+			problemType: handlers.ProblemTypeInvalidParams,
+			expected:    "limit should be at least 0",
+		},
+		{
+			name:        "invalid last event id",
+			headers:     []interface{}{"Last-Event-ID", "last"},
+			problemType: handlers.ProblemTypeInvalidParams,
+			expected:    "Last-Event-ID should be a term index",
+		},
+	}
+	for _, tc := range testCases {
+		testAPI.Run(tc.name, func(ta *tdhttp.TestAPI) {
+			ta.Get("/fizzbuzz/live?"+tc.query, tc.headers...).
+				CmpStatus(http.StatusBadRequest).
+				CmpJSONBody(td.JSON(`SuperMapOf({"type": $1, "detail": $2})`, tc.problemType, tc.expected))
+		})
+	}
+}
+
+// liveTerm is a term received on a live WebSocket, or the error ending it.
+type liveTerm struct {
+	term handlers.FizzBuzzTermOutput
+	err  error
+}
+
+// dialLive opens a live WebSocket, its terms being sent on the returned
+// channel until the socket is closed.
+func dialLive(t *testing.T, url string) (*websocket.Conn, <-chan liveTerm) {
+	conn, res, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(url, "http"), nil)
+	td.Require(t).CmpNoError(err)
+	res.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+
+	terms := make(chan liveTerm, 100)
+	go func() {
+		defer close(terms)
+		for {
+			var lt liveTerm
+			if lt.err = conn.ReadJSON(&lt.term); lt.err != nil {
+				terms <- lt
+				return
+			}
+			terms <- lt
+		}
+	}()
+	return conn, terms
+}
+
+func TestFizzBuzzLiveWebSocket(t *testing.T) {
+	srv := httptest.NewServer(server.New())
+	defer srv.Close()
+
+	_, terms := dialLive(t, srv.URL+"/fizzbuzz/live?limit=3&rate=100")
+
+	var values []string
+	var end error
+	for lt := range terms {
+		if lt.err != nil {
+			end = lt.err
+			break
+		}
+		values = append(values, lt.term.Value)
+	}
+	td.Cmp(t, values, []string{"1", "2", "fizz"})
+	td.Cmp(t, end, &websocket.CloseError{Code: websocket.CloseNormalClosure, Text: "completed"})
+
+	conn, terms := dialLive(t, srv.URL+"/fizzbuzz/live?limit=10&rate=20")
+
+	td.Cmp(t, (<-terms).term.Value, "1")
+	td.Require(t).CmpNoError(conn.WriteMessage(websocket.TextMessage, []byte("pause")))
+
+	// 6 terms would be emitted meanwhile, one may have been before pausing
+	time.Sleep(300 * time.Millisecond)
+	td.Cmp(t, len(terms), td.Between(0, 1))
+
+	td.Require(t).CmpNoError(conn.WriteMessage(websocket.TextMessage, []byte("resume")))
+
+	values = []string{"1"}
+	for lt := range terms {
+		if lt.err != nil {
+			end = lt.err
+			break
+		}
+		values = append(values, lt.term.Value)
+	}
+	td.Cmp(t, values, []string{"1", "2", "fizz", "4", "buzz", "fizz", "7", "8", "fizz", "buzz"})
+	td.Cmp(t, end, &websocket.CloseError{Code: websocket.CloseNormalClosure, Text: "completed"})
+}
+
+func TestFizzBuzzLiveShutdown(t *testing.T) {
+	e := server.New()
+	e.Server.Handler = e
+	srv := httptest.NewUnstartedServer(e)
+	srv.Config = e.Server
+	srv.Start()
+	defer srv.Close()
+
+	_, terms := dialLive(t, srv.URL+"/fizzbuzz/live?rate=10")
+
+	res, err := http.Get(srv.URL + "/fizzbuzz/live?rate=10")
+	td.Require(t).CmpNoError(err)
+	defer res.Body.Close()
+
+	events := bufio.NewReader(res.Body)
+	line, err := events.ReadString('\n')
+	td.Require(t).CmpNoError(err)
+	td.Cmp(t, line, "id: 0\n")
+	td.Cmp(t, (<-terms).term.Value, "1")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	td.CmpNoError(t, e.Shutdown(ctx))
+
+	var end error
+	for lt := range terms {
+		end = lt.err
+	}
+	td.Cmp(t, end, &websocket.CloseError{Code: websocket.CloseGoingAway, Text: "shutdown"})
+
+	var body strings.Builder
+	_, err = events.WriteTo(&body)
+	td.CmpNoError(t, err)
+	td.Cmp(t, body.String(), td.HasSuffix("event: end\ndata: \"shutdown\"\n\n"))
+}
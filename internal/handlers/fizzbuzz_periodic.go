@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strconv"
+
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
+	"github.com/labstack/echo/v4"
+)
+
+// Response shapes of GET /fizzbuzz.
+const (
+	ShapeList     = "list"
+	ShapePeriodic = "periodic"
+)
+
+// periodicFormats are the content types GET /fizzbuzz may respond with,
+// when requested with shape=periodic.
+var periodicFormats = []string{
+	echo.MIMEApplicationJSON,
+	MIMEApplicationMsgpack,
+}
+
+// FizzBuzzPeriodicOutput describes the response output for the fizzbuzz
+// handler, when requested with shape=periodic.
+//
+// Divisible rules make the terms periodic: the k-th term of the range,
+// k starting from 0 and lower than Count, is Pattern[k % len(Pattern)].
+// Null pattern entries are numeric slots, where the term is From + k*Step
+// written according to Numbers, spelled out in Language for words.
+type FizzBuzzPeriodicOutput struct {
+	Pattern  []*string   `json:"pattern"`
+	From     json.Number `json:"from" swaggertype:"integer"`
+	Step     int         `json:"step"`
+	Count    json.Number `json:"count" swaggertype:"integer"`
+	Numbers  string      `json:"numbers"`
+	Language string      `json:"language,omitempty"`
+}
+
+// periodicFizzBuzz responds with one period of the input's terms.
+//
+// It assumes that SetDefault method was called on the FizzBuzzInput instance
+// so that all values are non-nil.
+func periodicFizzBuzz(c echo.Context, in FizzBuzzInput, rs *fizzbuzz.Rules, mime string) error {
+	out, err := in.periodic(c.Logger(), rs, mime)
+	if err != nil {
+		return err
+	}
+
+	// inputs are valid, add this request to fizzbuzz's stats
+	go in.Register()
+
+	if notModified(c, fizzBuzzETag(c, in, mime), cacheControlImmutable) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return render(c, http.StatusOK, mime, out)
+}
+
+// periodic computes one period of the input's terms, once checked against
+// the servers thresholds.
+func (in FizzBuzzInput) periodic(logger echo.Logger, rs *fizzbuzz.Rules, mime string) (FizzBuzzPeriodicOutput, error) {
+	g := in.generator(rs, 0)
+
+	period := g.Period()
+	if period == nil {
+		logger.Warn("periodic shape requested with non divisible rules")
+		return FizzBuzzPeriodicOutput{}, invalidParams(newInvalidParam("rule", in.rulesValue(), "divisible", "divisible-only", "shape=periodic"))
+	}
+
+	count := g.Len()
+	size := period
+	if count.Cmp(size) < 0 {
+		size = count
+	}
+
+	if size.Cmp(big.NewInt(int64(FizzBuzzMaxLimit))) > 0 {
+		logger.Warnf("period %d is higher than threshold %d", size, FizzBuzzMaxLimit)
+		threshold := strconv.Itoa(FizzBuzzMaxLimit)
+		return FizzBuzzPeriodicOutput{}, invalidParams(newInvalidParam("period", size.String(), "max="+threshold, "lower-than", threshold))
+	}
+
+	err := checkBudget(logger, responseSize(in, rs, 0, size.Uint64(), mime), FizzBuzzMaxBytes)
+	if err != nil {
+		return FizzBuzzPeriodicOutput{}, err
+	}
+
+	out := FizzBuzzPeriodicOutput{
+		Pattern:  make([]*string, size.Int64()),
+		From:     json.Number(in.From.String()),
+		Step:     *in.Step,
+		Count:    json.Number(count.String()),
+		Numbers:  in.Numbers,
+		Language: in.numbers.Language(),
+	}
+	if out.Numbers == "" {
+		out.Numbers = fizzbuzz.NumbersDecimal
+	}
+
+	for i := range out.Pattern {
+		matching := len(rs.Matching(g.Value())) > 0
+		term, _ := g.Next()
+		if matching {
+			out.Pattern[i] = &term
+		}
+	}
+	return out, nil
+}
@@ -1,11 +1,54 @@
 package handlers
 
 import (
+	"encoding/xml"
+	"fmt"
 	"net/http"
+	"strconv"
 
+	"github.com/c-roussel/fizzbuzz-api/internal/stats"
 	"github.com/labstack/echo/v4"
 )
 
+// statsFormats are the content types GET /fizzbuzz/stats may respond with.
+var statsFormats = []string{
+	echo.MIMEApplicationJSON,
+	echo.MIMEApplicationXML,
+	MIMEApplicationMsgpack,
+	MIMETextPlain,
+	MIMETextCSV,
+}
+
+// statsOutput is the list of stats.Count responded by FizzBuzzStats.
+type statsOutput []stats.Count
+
+// MarshalXML wraps the counts in a stats element.
+func (out statsOutput) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(struct {
+		Counts []stats.Count `xml:"count"`
+	}{out}, xml.StartElement{Name: xml.Name{Local: "stats"}})
+}
+
+func (out statsOutput) header() []string {
+	return []string{"key", "hit"}
+}
+
+func (out statsOutput) rows() [][]string {
+	rows := make([][]string, len(out))
+	for i, count := range out {
+		rows[i] = []string{count.Key, strconv.Itoa(count.Hit)}
+	}
+	return rows
+}
+
+func (out statsOutput) lines() []string {
+	lines := make([]string, len(out))
+	for i, count := range out {
+		lines[i] = strconv.Itoa(count.Hit) + "\t" + count.Key
+	}
+	return lines
+}
+
 // FizzBuzzStats responds to GET /fizbuzz/stats HTTP requests.
 //
 // It will respond with a 200 HTTP repsonse embedding
@@ -15,16 +58,40 @@ import (
 //  - Every succesful GET /fizzbuzz will increment its parameters's stats
 //  - Respond with the top 100 stats
 //
+// The response is encoded according to the `format` parameter or the
+// Accept header, the same way as FizzBuzz.
+//
+// Its ETag changes along with the statistics, If-None-Match requests being
+// answered with 304 responses while they are unchanged.
+//
 // @Summary Top 100 /fizzbuzz parameters.
 // @Description Get the 100 most used parameters on GET /fizbuzz route.
 // @Tags fizzbuzz
 // @Accept */*
-// @Produce json
+// @Param format query string false "response's format, overrides Accept header" Enums(json, xml, text, csv, msgpack)
+// @Produce json,application/xml,plain,text/csv,application/msgpack
+// @Param If-None-Match header string false "ETag of a previous response"
 // @Success 200 {array} stats.Count
+// @Success 304 "Not Modified"
+// @Failure 406 {object} handlers.Problem
 // @Router /fizzbuzz/stats [get]
 func FizzBuzzStats(c echo.Context) error {
+	mime, err := negotiate(c, c.QueryParam("format"), statsFormats...)
+	if err != nil {
+		c.Logger().Warnf("failed to negotiate response format: %v", err)
+		return err
+	}
+
+	// the version is read first so that the response is never older than
+	// its ETag
+	etag := newETag(fmt.Sprintf("stats version=%d mime=%s pretty=%t",
+		fizzBuzzGatherer.Version(), mime, !cacheable(c)))
+	if notModified(c, etag, cacheControlRevalidate) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
 	res := fizzBuzzGatherer.OrderedValues()
-	return c.JSON(http.StatusOK, res[:min(len(res), 100)])
+	return render(c, http.StatusOK, mime, statsOutput(res[:min(len(res), 100)]))
 }
 
 func min(a, b int) int {
@@ -57,3 +57,74 @@ func TestFizzBuzzStats(t *testing.T) {
 		CmpStatus(http.StatusOK).
 		CmpJSONBody(td.JSON("Len(100)"))
 }
+
+func TestFizzBuzzStatsFormats(t *testing.T) {
+	handlers.ExportFizzBuzzGatherer.Reset()
+
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testAPI.Name("/fizzbuzz stat population").
+		Get("/fizzbuzz?str1=le&str2=boncoin&limit=6&int1=2&int2=3").
+		CmpStatus(http.StatusOK)
+
+	// gathering is done asynchronously
+	time.Sleep(100 * time.Millisecond)
+
+	testAPI.Name("/fizzbuzz stat retrieval as csv").
+		Get("/fizzbuzz/stats?format=csv").
+		CmpStatus(http.StatusOK).
+		CmpBody("key,hit\nFizzBuzzInput str1=le str2=boncoin int1=2 int2=3 limit=6,1\n")
+
+	testAPI.Name("/fizzbuzz stat retrieval as text").
+		Get("/fizzbuzz/stats", "Accept", "text/plain").
+		CmpStatus(http.StatusOK).
+		CmpBody("1\tFizzBuzzInput str1=le str2=boncoin int1=2 int2=3 limit=6\n")
+
+	testAPI.Name("/fizzbuzz stat retrieval as xml").
+		Get("/fizzbuzz/stats?format=xml").
+		CmpStatus(http.StatusOK).
+		CmpBody(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+			`<stats><count><key>FizzBuzzInput str1=le str2=boncoin int1=2 int2=3 limit=6</key>` +
+			`<hit>1</hit></count></stats>`)
+
+	testAPI.Name("/fizzbuzz stat retrieval as ndjson is not supported").
+		Get("/fizzbuzz/stats?format=ndjson").
+		CmpStatus(http.StatusNotAcceptable).
+		CmpJSONBody(td.JSON(`SuperMapOf({"type": "about:blank", "detail": "unsupported format \"ndjson\""})`))
+}
+
+func TestFizzBuzzStatsETag(t *testing.T) {
+	handlers.ExportFizzBuzzGatherer.Reset()
+
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	var etag string
+	testAPI.Name("first poll").
+		Get("/fizzbuzz/stats").
+		CmpStatus(http.StatusOK).
+		CmpHeader(td.SuperMapOf(http.Header{
+			"Cache-Control": {"no-cache"},
+		}, td.MapEntries{
+			"Etag": td.Bag(td.Catch(&etag, td.Re(`^"[0-9a-f]{32}"$`))),
+		}))
+
+	testAPI.Name("unchanged stats").
+		Get("/fizzbuzz/stats", "If-None-Match", etag).
+		CmpStatus(http.StatusNotModified)
+
+	testAPI.Name("other format").
+		Get("/fizzbuzz/stats?format=csv", "If-None-Match", etag).
+		CmpStatus(http.StatusOK)
+
+	testAPI.Name("/fizzbuzz stat population").
+		Get("/fizzbuzz?limit=6").
+		CmpStatus(http.StatusOK)
+
+	// gathering is done asynchronously
+	time.Sleep(100 * time.Millisecond)
+
+	testAPI.Name("changed stats").
+		Get("/fizzbuzz/stats", "If-None-Match", etag).
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`[{"key": "FizzBuzzInput str1=fizz str2=buzz int1=3 int2=5 limit=6", "hit": 1}]`))
+}
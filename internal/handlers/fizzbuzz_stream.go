@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
+	"github.com/labstack/echo/v4"
+)
+
+// MIMEApplicationNDJSON is the newline delimited JSON content type used
+// to stream fizzbuzz results.
+const MIMEApplicationNDJSON = "application/x-ndjson"
+
+// FizzBuzzEnvStreamLimit is the environment variable to override the
+// servers maximum limit on streamed GET /fizzbuzz responses.
+const FizzBuzzEnvStreamLimit = "FIZZBUZZ_STREAM_MAX_LIMIT"
+
+// FizzBuzzStreamMaxLimit is the maximum number of terms of a streamed
+// GET /fizzbuzz response.
+//
+// Streamed responses are written using constant memory, hence a much higher
+// threshold than FizzBuzzMaxLimit.
+var FizzBuzzStreamMaxLimit = 1000000000
+
+// streamFlushSize is the number of terms written between two flushes.
+const streamFlushSize = 1024
+
+// streamFizzBuzz writes count fizzbuzz terms as newline delimited JSON
+// strings, through a chunked response flushed every streamFlushSize terms.
+//
+// It stops as soon as the client disconnects.
+func streamFizzBuzz(c echo.Context, in FizzBuzzInput, rs *fizzbuzz.Rules, count uint64) error {
+	ctx := c.Request().Context()
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+	res.WriteHeader(http.StatusOK)
+
+	w := bufio.NewWriter(res)
+	enc := json.NewEncoder(w)
+	g := in.generator(rs, 0)
+
+	for i := uint64(0); i < count; i++ {
+		if i%streamFlushSize == 0 && i > 0 {
+			if err := w.Flush(); err != nil {
+				c.Logger().Warnf("failed to stream fizzbuzz terms: %v", err)
+				return nil
+			}
+			res.Flush()
+
+			if err := ctx.Err(); err != nil {
+				c.Logger().Infof("fizzbuzz stream interrupted after %d terms: %v", i, err)
+				return nil
+			}
+		}
+
+		term, _ := g.Next()
+		if err := enc.Encode(term); err != nil {
+			c.Logger().Warnf("failed to stream fizzbuzz terms: %v", err)
+			return nil
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		c.Logger().Warnf("failed to stream fizzbuzz terms: %v", err)
+		return nil
+	}
+	res.Flush()
+	return nil
+}
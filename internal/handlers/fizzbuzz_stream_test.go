@@ -0,0 +1,77 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/handlers"
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"github.com/maxatome/go-testdeep/helpers/tdhttp"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestFizzBuzzStream(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testAPI.Name("stream with query param").
+		Get("/fizzbuzz?stream=true&limit=6&int1=2&int2=3&str1=le&str2=boncoin").
+		CmpStatus(http.StatusOK).
+		CmpHeader(td.SuperMapOf(http.Header{
+			"Content-Type": {handlers.MIMEApplicationNDJSON},
+		}, nil)).
+		CmpBody("\"1\"\n\"le\"\n\"boncoin\"\n\"le\"\n\"5\"\n\"leboncoin\"\n")
+
+	testAPI.Name("stream with accept header").
+		Get("/fizzbuzz?from=-1&to=1", "Accept", handlers.MIMEApplicationNDJSON).
+		CmpStatus(http.StatusOK).
+		CmpBody("\"-1\"\n\"fizzbuzz\"\n\"1\"\n")
+
+	testAPI.Name("stream with roman numbers").
+		Get("/fizzbuzz?stream=true&limit=4&numbers=roman").
+		CmpStatus(http.StatusOK).
+		CmpBody("\"I\"\n\"II\"\n\"fizz\"\n\"IV\"\n")
+
+	testAPI.Name("stream above max limit").
+		Get("/fizzbuzz?stream=true&limit=20000").
+		CmpStatus(http.StatusOK).
+		CmpBody(td.Code(func(body string) bool {
+			return bytes.Count([]byte(body), []byte("\n")) == 20000
+		}))
+
+	testAPI.Name("stream above stream max limit").
+		Get("/fizzbuzz?stream=true&from=0&to=1000000000").
+		CmpStatus(http.StatusBadRequest).
+		CmpJSONBody(td.JSON(`SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "limit", "value": "1000000001", "constraint": "max=1000000000", "reason": "limit should be lower than 1000000000"}]})`))
+}
+
+func TestFizzBuzzStreamClientDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := httptest.NewRequest(http.MethodGet, "/fizzbuzz?stream=true&limit=1000000", nil).
+		WithContext(ctx)
+	rec := httptest.NewRecorder()
+	server.New().ServeHTTP(rec, req)
+
+	td.Cmp(t, rec.Code, http.StatusOK)
+	td.CmpLt(t, bytes.Count(rec.Body.Bytes(), []byte("\n")), 1000000,
+		"stream stopped before writing all terms")
+}
+
+func TestFizzBuzzStreamHundredsOfMillions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// way above FizzBuzzMaxBytes, streams have no byte budget
+	req := httptest.NewRequest(http.MethodGet, "/fizzbuzz?stream=true&limit=100000000", nil).
+		WithContext(ctx)
+	rec := httptest.NewRecorder()
+	server.New().ServeHTTP(rec, req)
+
+	td.Cmp(t, rec.Code, http.StatusOK)
+	td.CmpLt(t, bytes.Count(rec.Body.Bytes(), []byte("\n")), 100000000,
+		"stream stopped before writing all terms")
+}
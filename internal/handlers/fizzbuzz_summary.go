@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
+	"github.com/labstack/echo/v4"
+)
+
+// FizzBuzzSummaryMaxRules is the maximum number of rules of a
+// GET /fizzbuzz/summary request, every combination of rules being counted.
+const FizzBuzzSummaryMaxRules = 8
+
+// summaryFormats are the content types GET /fizzbuzz/summary may respond
+// with.
+var summaryFormats = []string{
+	echo.MIMEApplicationJSON,
+	echo.MIMEApplicationXML,
+	MIMEApplicationMsgpack,
+}
+
+// FizzBuzzSummaryOutput describes the response output for the fizzbuzz
+// summary handler.
+//
+// Count is the number of terms in the range. Words holds one entry per
+// combination of matching rules, in the same order as the rules bitmask,
+// and Numbers the terms matching no rule.
+type FizzBuzzSummaryOutput struct {
+	XMLName xml.Name               `json:"-" xml:"summary"`
+	Count   json.Number            `json:"count" xml:"count" swaggertype:"integer"`
+	Words   []FizzBuzzSummaryCount `json:"words" xml:"words>word"`
+	Numbers FizzBuzzSummaryCount   `json:"numbers" xml:"numbers"`
+}
+
+// FizzBuzzSummaryCount counts the terms replaced by a given value.
+//
+// First is the first value of the range replaced by Value, it is null when
+// Count is 0. Density is Count's ratio among all terms.
+type FizzBuzzSummaryCount struct {
+	Value   string       `json:"value,omitempty" xml:"value,omitempty"`
+	Matched []string     `json:"matched,omitempty" xml:"matched>rule,omitempty"`
+	Count   json.Number  `json:"count" xml:"count" swaggertype:"integer"`
+	First   *json.Number `json:"first" xml:"first,omitempty" swaggertype:"integer"`
+	Density float64      `json:"density" xml:"density"`
+}
+
+// FizzBuzzSummary responds to GET /fizbuzz/summary HTTP requests.
+//
+// It will respond with a 200 HTTP repsonse embedding
+// a FizzBuzzSummaryOutput result.
+//
+// It counts the terms FizzBuzz would return for each combination of words,
+// e.g. str1, str2 and str1+str2, and the remaining numbers, without
+// computing any term. Counts rely on the inclusion–exclusion principle
+// over the divisors lcm, hence any range size is answered instantly.
+//
+// Only divisible rules are supported, without combination overrides.
+//
+// @Summary Customizable fizzbuzz algorithm summary.
+// @Description Count the words and numbers of your own version of the fizzbuzz algortihm.
+// @Tags fizzbuzz
+// @Accept */*
+// @Param int1  query int      false "fizzbuzz's first multiple"     minimum(1) default(3)
+// @Param int2  query int      false "fizzbuzz's second multiple"    minimum(1) default(5)
+// @Param str1  query string   false "fizzbuzz's first replacement"             default(fizz)
+// @Param str2  query string   false "fizzbuzz's second replacement"            default(buzz)
+// @Param rule  query []string false "fizzbuzz's divisible rules, as divisor:word" collectionFormat(multi)
+// @Param limit query int      false "fizzbuzz's up-to value"        minimum(0) default(100)
+// @Param from  query int      false "fizzbuzz's starting value"                default(1)
+// @Param to    query int      false "fizzbuzz's up-to value, replaces limit"
+// @Param step  query int      false "fizzbuzz's increment"          minimum(1) default(1)
+// @Param combine query string false "fizzbuzz's combination of several matching words" Enums(concat, first) default(concat)
+// @Param separator query string false "fizzbuzz's separator of concatenated words"
+// @Param reverse query bool false "fizzbuzz's combination from the last matching word" default(false)
+// @Param format query string false "response's format, overrides Accept header" Enums(json, xml, msgpack)
+// @Produce json,application/xml,application/msgpack
+// @Success 200 {object} handlers.FizzBuzzSummaryOutput
+// @Failure 400 {object} handlers.Problem
+// @Router /fizzbuzz/summary [get]
+func FizzBuzzSummary(c echo.Context) error {
+	var in FizzBuzzInput
+	err := c.Bind(&in)
+	if err != nil {
+		c.Logger().Warnf("failed to parse query parameters: %v", err)
+		return err
+	}
+
+	err = validateFizzBuzzInput(c, &in)
+	if err != nil {
+		return err
+	}
+
+	mime, err := negotiate(c, in.Format, summaryFormats...)
+	if err != nil {
+		c.Logger().Warnf("failed to negotiate response format: %v", err)
+		return err
+	}
+
+	rs, err := in.newRules(c.Logger())
+	if err != nil {
+		return err
+	}
+
+	if rs.Period() == nil {
+		c.Logger().Warn("summary requested with non divisible rules")
+		return invalidParams(newInvalidParam("rule", in.rulesValue(), "divisible", "divisible-only", "summary"))
+	}
+
+	if len(in.Overrides) > 0 {
+		c.Logger().Warn("summary requested with overrides")
+		return invalidParams(newInvalidParam("override", in.overridesValue(), "excluded_with=summary", "summary-override"))
+	}
+
+	if len(rs.Rules()) > FizzBuzzSummaryMaxRules {
+		c.Logger().Warnf("%d rules is higher than threshold %d", len(rs.Rules()), FizzBuzzSummaryMaxRules)
+		threshold := strconv.Itoa(FizzBuzzSummaryMaxRules)
+		return invalidParams(newInvalidParam("rule", in.rulesValue(), "max="+threshold, "max-items", threshold))
+	}
+
+	summary, err := in.generator(rs, 0).Summary()
+	if err != nil {
+		c.Logger().Warnf("failed to summarize: %v", err)
+		return invalidParams(in.rulesParam(err))
+	}
+	return render(c, http.StatusOK, mime, newFizzBuzzSummaryOutput(in, summary))
+}
+
+// newFizzBuzzSummaryOutput describes a fizzbuzz.Summary of the input's
+// range, naming its rules.
+func newFizzBuzzSummaryOutput(in FizzBuzzInput, s fizzbuzz.Summary) FizzBuzzSummaryOutput {
+	names := in.ruleNames()
+	out := FizzBuzzSummaryOutput{
+		Count:   json.Number(s.Count.String()),
+		Words:   make([]FizzBuzzSummaryCount, len(s.Words)),
+		Numbers: newFizzBuzzSummaryCount(s.Numbers),
+	}
+	for i, words := range s.Words {
+		out.Words[i] = newFizzBuzzSummaryCount(words)
+		out.Words[i].Value = words.Word
+		for _, r := range words.Rules {
+			out.Words[i].Matched = append(out.Words[i].Matched, names[r])
+		}
+	}
+	return out
+}
+
+// newFizzBuzzSummaryCount describes a fizzbuzz.SummaryCount, its value
+// and matched rules excepted.
+func newFizzBuzzSummaryCount(sc fizzbuzz.SummaryCount) FizzBuzzSummaryCount {
+	out := FizzBuzzSummaryCount{Count: json.Number(sc.Count.String()), Density: sc.Density}
+	if sc.First != nil {
+		n := json.Number(sc.First.String())
+		out.First = &n
+	}
+	return out
+}
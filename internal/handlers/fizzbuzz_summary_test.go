@@ -0,0 +1,117 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"github.com/maxatome/go-testdeep/helpers/tdhttp"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestFizzBuzzSummary(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testCases := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedJSON   string
+	}{
+		{
+			name:           "default values",
+			url:            "/fizzbuzz/summary",
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{
+				"count": 100,
+				"words": [
+					{"value": "fizz", "matched": ["int1"], "count": 27, "first": 3, "density": 0.27},
+					{"value": "buzz", "matched": ["int2"], "count": 14, "first": 5, "density": 0.14},
+					{"value": "fizzbuzz", "matched": ["int1", "int2"], "count": 6, "first": 15, "density": 0.06}
+				],
+				"numbers": {"count": 53, "first": 1, "density": 0.53}
+			}`,
+		},
+		{
+			name:           "range with step",
+			url:            "/fizzbuzz/summary?from=0&to=30&step=2",
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{
+				"count": 16,
+				"words": [
+					{"value": "fizz", "matched": ["int1"], "count": 4, "first": 6, "density": 0.25},
+					{"value": "buzz", "matched": ["int2"], "count": 2, "first": 10, "density": 0.125},
+					{"value": "fizzbuzz", "matched": ["int1", "int2"], "count": 2, "first": 0, "density": 0.125}
+				],
+				"numbers": {"count": 8, "first": 2, "density": 0.5}
+			}`,
+		},
+		{
+			name:           "words never used",
+			url:            "/fizzbuzz/summary?rule=2:a&rule=4:b&limit=5",
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{
+				"count": 5,
+				"words": [
+					{"value": "a", "matched": ["2:a"], "count": 1, "first": 2, "density": 0.2},
+					{"value": "b", "matched": ["4:b"], "count": 0, "first": null, "density": 0},
+					{"value": "ab", "matched": ["2:a", "4:b"], "count": 1, "first": 4, "density": 0.2}
+				],
+				"numbers": {"count": 3, "first": 1, "density": 0.6}
+			}`,
+		},
+		{
+			name:           "empty range",
+			url:            "/fizzbuzz/summary?rule=2:a&limit=0",
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{
+				"count": 0,
+				"words": [{"value": "a", "matched": ["2:a"], "count": 0, "first": null, "density": 0}],
+				"numbers": {"count": 0, "first": null, "density": 0}
+			}`,
+		},
+		{
+			name:           "non divisible rules",
+			url:            "/fizzbuzz/summary?rule=is_prime:p",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "rule", "value": "is_prime:p", "constraint": "divisible", "reason": "rule should only hold divisible rules to use summary"}]})`,
+		},
+		{
+			name:           "overrides",
+			url:            "/fizzbuzz/summary?combine=override&override=15:bingo",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "override", "value": "15:bingo", "constraint": "excluded_with=summary", "reason": "summary does not support override"}]})`,
+		},
+		{
+			name:           "too many rules",
+			url:            "/fizzbuzz/summary?rule=2:a&rule=3:b&rule=5:c&rule=7:d&rule=11:e&rule=13:f&rule=17:g&rule=19:h&rule=23:i",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "rule", "value": "2:a,3:b,5:c,7:d,11:e,13:f,17:g,19:h,23:i", "constraint": "max=8", "reason": "rule should hold at most 8 items"}]})`,
+		},
+		{
+			name:           "invalid int1 query param",
+			url:            "/fizzbuzz/summary?int1=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "int1", "value": "0", "constraint": "min=1", "reason": "int1 should be at least 1"}]})`,
+		},
+	}
+	for _, tc := range testCases {
+		testAPI.Run(tc.name, func(ta *tdhttp.TestAPI) {
+			ta.Get(tc.url).
+				CmpStatus(tc.expectedStatus).
+				CmpJSONBody(td.JSON(tc.expectedJSON))
+		})
+	}
+
+	// td.JSON would lose big numbers precision, compare raw bodies instead
+	testAPI.Name("limit far beyond the max limit").
+		Get("/fizzbuzz/summary?limit=1000000000000000000").
+		CmpStatus(http.StatusOK).
+		CmpBody(td.All(
+			td.Contains(`"count":1000000000000000000,`),
+			td.Contains(`{"value":"fizz","matched":["int1"],"count":266666666666666667,"first":3,`),
+			td.Contains(`{"value":"buzz","matched":["int2"],"count":133333333333333334,"first":5,`),
+			td.Contains(`{"value":"fizzbuzz","matched":["int1","int2"],"count":66666666666666666,"first":15,`),
+			td.Contains(`"numbers":{"count":533333333333333333,"first":1,`),
+		))
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"math/big"
+	"net/http"
+
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
+	"github.com/labstack/echo/v4"
+)
+
+// termFormats are the content types GET /fizzbuzz/{n} may respond with.
+var termFormats = []string{
+	echo.MIMEApplicationJSON,
+	echo.MIMEApplicationXML,
+	MIMEApplicationMsgpack,
+}
+
+// FizzBuzzTermOutput describes the response output for the fizzbuzz term
+// handler.
+//
+// N is a number, which may exceed int64. Matched names the rules
+// matching N, see FizzBuzzTerm.
+type FizzBuzzTermOutput struct {
+	XMLName xml.Name    `json:"-" xml:"term"`
+	N       json.Number `json:"n" xml:"n" swaggertype:"integer"`
+	Value   string      `json:"value" xml:"value"`
+	Matched []string    `json:"matched" xml:"matched>rule"`
+}
+
+// FizzBuzzTerm responds to GET /fizbuzz/{n} HTTP requests.
+//
+// It will respond with a 200 HTTP repsonse embedding
+// a FizzBuzzTermOutput result.
+//
+// The n-th term is computed with the same rules as FizzBuzz, without
+// computing any other term. Range parameters, such as limit, are not used.
+// n may exceed int64, the term is then computed with arbitrary precision.
+//
+// Matched rules are named int1 and int2 when using the int1/int2/str1/str2
+// shorthand, or with their `rule` query parameter notation otherwise.
+//
+// @Summary Single fizzbuzz term.
+// @Description Get the value of the n-th term of your own version of the fizzbuzz algortihm.
+// @Tags fizzbuzz
+// @Accept */*
+// @Param n     path  int      true  "fizzbuzz's term position"
+// @Param int1  query int      false "fizzbuzz's first multiple"     minimum(1) default(3)
+// @Param int2  query int      false "fizzbuzz's second multiple"    minimum(1) default(5)
+// @Param str1  query string   false "fizzbuzz's first replacement"             default(fizz)
+// @Param str2  query string   false "fizzbuzz's second replacement"            default(buzz)
+// @Param rule  query []string false "fizzbuzz's rules, as divisor:word or kind:arg:word" collectionFormat(multi)
+// @Param combine query string false "fizzbuzz's combination of several matching words" Enums(concat, first, override) default(concat)
+// @Param separator query string false "fizzbuzz's separator of concatenated words"
+// @Param reverse query bool false "fizzbuzz's combination from the last matching word" default(false)
+// @Param override query []string false "fizzbuzz's combination overrides, as divisor:word" collectionFormat(multi)
+// @Param numbers query string false "fizzbuzz's numbers rendering, as decimal, hex, binary, base:N, roman, padded:N or words" default(decimal)
+// @Param format query string false "response's format, overrides Accept header" Enums(json, xml, msgpack)
+// @Produce json,application/xml,application/msgpack
+// @Success 200 {object} handlers.FizzBuzzTermOutput
+// @Failure 400 {object} handlers.Problem
+// @Router /fizzbuzz/{n} [get]
+func FizzBuzzTerm(c echo.Context) error {
+	n, ok := new(big.Int).SetString(c.Param("n"), 10)
+	if !ok {
+		c.Logger().Warnf("failed to parse term position %q", c.Param("n"))
+		return invalidParams(newInvalidParam("n", c.Param("n"), "integer", "type-integer"))
+	}
+
+	var in FizzBuzzInput
+	err := c.Bind(&in)
+	if err != nil {
+		c.Logger().Warnf("failed to parse query parameters: %v", err)
+		return err
+	}
+
+	// a single term does not depend on the range
+	in.From, in.To, in.Step, in.Limit = nil, nil, nil, nil
+
+	err = validateFizzBuzzInput(c, &in)
+	if err != nil {
+		return err
+	}
+
+	mime, err := negotiate(c, in.Format, termFormats...)
+	if err != nil {
+		c.Logger().Warnf("failed to negotiate response format: %v", err)
+		return err
+	}
+
+	rs, err := in.newRules(c.Logger())
+	if err != nil {
+		return err
+	}
+
+	return render(c, http.StatusOK, mime, fizzBuzzTerm(in, rs, n))
+}
+
+// fizzBuzzTerm computes the n-th term along with the rules it matches.
+func fizzBuzzTerm(in FizzBuzzInput, rs *fizzbuzz.Rules, n *big.Int) FizzBuzzTermOutput {
+	names := in.ruleNames()
+	out := FizzBuzzTermOutput{
+		N:       json.Number(n.String()),
+		Value:   rs.Term(n),
+		Matched: []string{},
+	}
+	for _, i := range rs.Matching(n) {
+		out.Matched = append(out.Matched, names[i])
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"github.com/maxatome/go-testdeep/helpers/tdhttp"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestFizzBuzzTerm(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testCases := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedJSON   string
+	}{
+		{
+			name:           "default rules - both match",
+			url:            "/fizzbuzz/15",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"n": 15, "value": "fizzbuzz", "matched": ["int1", "int2"]}`,
+		},
+		{
+			name:           "default rules - none match",
+			url:            "/fizzbuzz/7",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"n": 7, "value": "7", "matched": []}`,
+		},
+		{
+			name:           "custom shorthand - negative position",
+			url:            "/fizzbuzz/-4?int1=2&str1=le",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"n": -4, "value": "le", "matched": ["int1"]}`,
+		},
+		{
+			name:           "rules",
+			url:            "/fizzbuzz/21?rule=3:fizz&rule=5:buzz&rule=7:woof",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"n": 21, "value": "fizzwoof", "matched": ["3:fizz", "7:woof"]}`,
+		},
+		{
+			name:           "position far beyond the max limit",
+			url:            "/fizzbuzz/1000000005",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"n": 1000000005, "value": "fizzbuzz", "matched": ["int1", "int2"]}`,
+		},
+		{
+			name:           "range parameters are not used",
+			url:            "/fizzbuzz/3?limit=-1",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"n": 3, "value": "fizz", "matched": ["int1"]}`,
+		},
+		{
+			name:           "invalid position",
+			url:            "/fizzbuzz/three",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "n", "value": "three", "constraint": "integer", "reason": "n should be an integer"}]})`,
+		},
+		{
+			name:           "invalid int1 query param",
+			url:            "/fizzbuzz/3?int1=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "int1", "value": "0", "constraint": "min=1", "reason": "int1 should be at least 1"}]})`,
+		},
+	}
+	for _, tc := range testCases {
+		testAPI.Run(tc.name, func(ta *tdhttp.TestAPI) {
+			ta.Get(tc.url).
+				CmpStatus(tc.expectedStatus).
+				CmpJSONBody(td.JSON(tc.expectedJSON))
+		})
+	}
+
+	// td.JSON would lose big numbers precision, compare raw bodies instead
+	testAPI.Name("position beyond int64").
+		Get("/fizzbuzz/1000000000000000000000000000000").
+		CmpStatus(http.StatusOK).
+		CmpBody(`{"n":1000000000000000000000000000000,"value":"buzz","matched":["int2"]}` + "\n")
+
+	testAPI.Name("divisor beyond int64").
+		Get("/fizzbuzz/200000000000000000000?rule=100000000000000000000:big&rule=3:fizz").
+		CmpStatus(http.StatusOK).
+		CmpBody(`{"n":200000000000000000000,"value":"big","matched":["100000000000000000000:big"]}` + "\n")
+
+	testAPI.Name("divisors lcm beyond int64").
+		Get("/fizzbuzz/18446744400127067027?rule=4294967311:a&rule=4294967357:b").
+		CmpStatus(http.StatusOK).
+		CmpBody(`{"n":18446744400127067027,"value":"ab","matched":["4294967311:a","4294967357:b"]}` + "\n")
+
+	testAPI.Name("xml format").
+		Get("/fizzbuzz/15?format=xml").
+		CmpStatus(http.StatusOK).
+		CmpBody(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+			`<term><n>15</n><value>fizzbuzz</value><matched><rule>int1</rule><rule>int2</rule></matched></term>`)
+}
@@ -1,16 +1,21 @@
 package handlers_test
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/c-roussel/fizzbuzz-api/internal/handlers"
 	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
+	"github.com/labstack/echo/v4"
 	"github.com/maxatome/go-testdeep/helpers/tdhttp"
 	"github.com/maxatome/go-testdeep/td"
+	"github.com/vmihailenco/msgpack/v5"
 )
 
 func TestFizzBuzz(t *testing.T) {
@@ -39,6 +44,238 @@ func TestFizzBuzz(t *testing.T) {
 			"74","fizzbuzz","76","77","fizz","79","buzz","fizz","82","83","fizz","buzz","86","fizz","88","89","fizzbuzz",
 			"91","92","fizz","94","buzz","fizz","97","98","fizz","buzz"
 			]}`,
+		}, {
+			name:           "valid call with rules",
+			url:            "/fizzbuzz?rule=3:fizz&rule=5:buzz&rule=7:woof&limit=21",
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{"result": [
+			"1","2","fizz","4","buzz","fizz","woof","8","fizz","buzz","11","fizz","13","woof","fizzbuzz",
+			"16","17","fizz","19","buzz","fizzwoof"
+			]}`,
+		}, {
+			name:           "valid call with rules - rule order is kept",
+			url:            "/fizzbuzz?rule=3:boncoin&rule=2:le&limit=6",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["1", "le", "boncoin", "le", "5", "boncoinle"]}`,
+		}, {
+			name:           "valid call with contains_digit rule",
+			url:            "/fizzbuzz?rule=contains_digit:3:fizz&limit=15",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["1","2","fizz","4","5","6","7","8","9","10","11","12","fizz","14","15"]}`,
+		}, {
+			name:           "valid call with divisible and contains_digit rules",
+			url:            "/fizzbuzz?rule=3:fizz&rule=contains_digit:3:fizz&limit=15",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["1","2","fizzfizz","4","5","fizz","7","8","fizz","10","11","fizz","fizz","14","fizz"]}`,
+		}, {
+			name:           "valid call with is_prime and is_square rules",
+			url:            "/fizzbuzz?rule=is_prime:p&rule=is_square:s&from=0&to=10",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["s","s","p","p","s","p","6","p","8","s","10"]}`,
+		}, {
+			name:           "valid call with in_range and ends_with rules",
+			url:            "/fizzbuzz?rule=in_range:3..5:x&rule=ends_with:5:five&limit=16",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["1","2","x","x","xfive","6","7","8","9","10","11","12","13","14","five","16"]}`,
+		}, {
+			name:           "valid call with from and to",
+			url:            "/fizzbuzz?from=1000000&to=1000005",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["buzz", "1000001", "fizz", "1000003", "1000004", "fizzbuzz"]}`,
+		}, {
+			name:           "valid call with step",
+			url:            "/fizzbuzz?step=10&limit=50",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["1", "11", "fizz", "31", "41"]}`,
+		}, {
+			name:           "valid call with zero and negative numbers",
+			url:            "/fizzbuzz?from=-5&to=3",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["buzz", "-4", "fizz", "-2", "-1", "fizzbuzz", "1", "2", "fizz"]}`,
+		}, {
+			name:           "valid call with a range beyond int64",
+			url:            "/fizzbuzz?from=1000000000000000000000000000000&to=1000000000000000000000000000005",
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{"result": [
+			"buzz","1000000000000000000000000000001","fizz",
+			"1000000000000000000000000000003","1000000000000000000000000000004","fizzbuzz"
+			]}`,
+		}, {
+			name:           "valid call with divisors lcm overflowing int64",
+			url:            "/fizzbuzz?from=0&to=2&rule=4294967311:a&rule=4294967357:b",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["ab", "1", "2"]}`,
+		}, {
+			name:           "valid call with to lower than from",
+			url:            "/fizzbuzz?from=5&to=3",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": []}`,
+		}, {
+			name:           "valid call with a range far beyond the max limit",
+			url:            "/fizzbuzz?from=1&to=1000000&step=1000",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": Len(1000)}`,
+		}, {
+			name:           "valid call with separator",
+			url:            "/fizzbuzz?from=14&to=15&separator=-",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["14", "fizz-buzz"]}`,
+		}, {
+			name:           "valid call with reversed concatenation",
+			url:            "/fizzbuzz?from=14&to=15&separator=%20&reverse=true",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["14", "buzz fizz"]}`,
+		}, {
+			name:           "valid call with first match",
+			url:            "/fizzbuzz?from=14&to=15&combine=first",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["14", "fizz"]}`,
+		}, {
+			name:           "valid call with reversed first match",
+			url:            "/fizzbuzz?from=14&to=15&combine=first&reverse=true",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["14", "buzz"]}`,
+		}, {
+			name:           "valid call with overrides",
+			url:            "/fizzbuzz?rule=2:a&rule=3:b&rule=5:c&override=6:bingo&override=10:bongo&limit=30",
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{"result": [
+			"1","a","b","a","c","bingo","7","a","b","bongo","11","bingo","13","a","bc",
+			"a","17","bingo","19","bongo","b","a","23","bingo","c","a","b","a","29","bingo"
+			]}`,
+		}, {
+			name:           "valid call with hex numbers",
+			url:            "/fizzbuzz?from=14&to=17&numbers=hex",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["e", "fizzbuzz", "10", "11"]}`,
+		}, {
+			name:           "valid call with binary numbers",
+			url:            "/fizzbuzz?limit=4&numbers=binary",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["1", "10", "fizz", "100"]}`,
+		}, {
+			name:           "valid call with base 36 numbers",
+			url:            "/fizzbuzz?from=34&to=37&numbers=base:36",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["y", "buzz", "fizz", "11"]}`,
+		}, {
+			name:           "valid call with roman numbers",
+			url:            "/fizzbuzz?from=3997&to=4001&numbers=roman",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["MMMCMXCVII", "MMMCMXCVIII", "fizz", "buzz", "4001"]}`,
+		}, {
+			name:           "valid call with zero-padded numbers",
+			url:            "/fizzbuzz?from=-2&to=2&numbers=padded:3",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["-002", "-001", "fizzbuzz", "001", "002"]}`,
+		}, {
+			name:           "valid call with spelled-out numbers",
+			url:            "/fizzbuzz?from=19&to=23&numbers=words",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["nineteen", "buzz", "fizz", "twenty-two", "twenty-three"]}`,
+		}, {
+			name:           "valid call with overrides - single matches are kept",
+			url:            "/fizzbuzz?rule=2:a&rule=3:b&override=2:bingo&limit=6",
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["1", "a", "b", "a", "5", "bingo"]}`,
+		},
+	}
+	for _, tc := range testCases {
+		testAPI.Run(tc.name, func(ta *tdhttp.TestAPI) {
+			ta.Get(tc.url).
+				CmpStatus(tc.expectedStatus).
+				CmpJSONBody(td.JSON(tc.expectedJSON))
+		})
+	}
+}
+
+func TestFizzBuzzPeriodic(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testCases := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedJSON   string
+	}{
+		{
+			name:           "default values",
+			url:            "/fizzbuzz?shape=periodic",
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{
+				"pattern": [null, null, "fizz", null, "buzz", "fizz", null, null, "fizz", "buzz", null, "fizz", null, null, "fizzbuzz"],
+				"from": 1,
+				"step": 1,
+				"count": 100,
+				"numbers": "decimal"
+			}`,
+		},
+		{
+			name:           "range far beyond the max limit",
+			url:            "/fizzbuzz?shape=periodic&from=0&to=1000000000&step=10&int1=4&int2=6&numbers=roman",
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{
+				"pattern": ["fizzbuzz", null, "fizz", "buzz", "fizz", null],
+				"from": 0,
+				"step": 10,
+				"count": 100000001,
+				"numbers": "roman"
+			}`,
+		},
+		{
+			name:           "range shorter than the period",
+			url:            "/fizzbuzz?shape=periodic&limit=4&numbers=words",
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{
+				"pattern": [null, null, "fizz", null],
+				"from": 1,
+				"step": 1,
+				"count": 4,
+				"numbers": "words",
+				"language": "en"
+			}`,
+		},
+		{
+			name:           "overrides",
+			url:            "/fizzbuzz?shape=periodic&rule=2:a&rule=3:b&override=4:bingo",
+			expectedStatus: http.StatusOK,
+			expectedJSON: `{
+				"pattern": [null, "a", "b", "a", null, "ab", null, "a", "b", "a", null, "bingo"],
+				"from": 1,
+				"step": 1,
+				"count": 100,
+				"numbers": "decimal"
+			}`,
+		},
+		{
+			name:           "non divisible rules",
+			url:            "/fizzbuzz?shape=periodic&rule=is_prime:p",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "rule", "value": "is_prime:p", "constraint": "divisible", "reason": "rule should only hold divisible rules to use shape=periodic"}]})`,
+		},
+		{
+			name:           "period above max limit",
+			url:            "/fizzbuzz?shape=periodic&int1=10007&int2=10009&limit=1000000000",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "period", "value": "100160063", "constraint": "max=10000", "reason": "period should be lower than 10000"}]})`,
+		},
+		{
+			name:           "along with stream",
+			url:            "/fizzbuzz?shape=periodic&stream=true",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "shape", "value": "periodic", "constraint": "excluded_with=stream cursor page_size", "reason": "shape=periodic cannot be used along with stream, cursor or page_size"}]})`,
+		},
+		{
+			name:           "unsupported format",
+			url:            "/fizzbuzz?shape=periodic&format=csv",
+			expectedStatus: http.StatusNotAcceptable,
+			expectedJSON:   `SuperMapOf({"type": "about:blank", "detail": "unsupported format \"csv\""})`,
+		},
+		{
+			name:           "invalid shape",
+			url:            "/fizzbuzz?shape=square",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "shape", "value": "square", "constraint": "oneof=list periodic", "reason": "shape should be one of list, periodic"}]})`,
 		},
 	}
 	for _, tc := range testCases {
@@ -50,6 +287,81 @@ func TestFizzBuzz(t *testing.T) {
 	}
 }
 
+func TestFizzBuzzETag(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	var etag string
+	testAPI.Name("first request").
+		Get("/fizzbuzz?limit=3").
+		CmpStatus(http.StatusOK).
+		CmpHeader(td.SuperMapOf(http.Header{
+			"Cache-Control": {"public, max-age=31536000, immutable"},
+			"Vary":          {"Accept, Accept-Language"},
+		}, td.MapEntries{
+			"Etag": td.Bag(td.Catch(&etag, td.Re(`^"[0-9a-f]{32}"$`))),
+		}))
+
+	testAPI.Name("same input").
+		Get("/fizzbuzz?limit=3", "If-None-Match", etag).
+		CmpStatus(http.StatusNotModified).
+		CmpBody("")
+
+	testAPI.Name("same normalized input").
+		Get("/fizzbuzz?rule=3:fizz&rule=5:buzz&from=1&to=3", "If-None-Match", `"other", `+etag).
+		CmpStatus(http.StatusNotModified)
+
+	testAPI.Name("other input").
+		Get("/fizzbuzz?limit=4", "If-None-Match", etag).
+		CmpStatus(http.StatusOK).
+		CmpHeader(td.SuperMapOf(http.Header{}, td.MapEntries{
+			"Etag": td.Not(td.Contains(etag)),
+		}))
+
+	testAPI.Name("other representation").
+		Get("/fizzbuzz?limit=3&format=xml", "If-None-Match", etag).
+		CmpStatus(http.StatusOK)
+
+	testAPI.Name("invalid input").
+		Get("/fizzbuzz?limit=-1", "If-None-Match", etag).
+		CmpStatus(http.StatusBadRequest)
+}
+
+func TestFizzBuzzNumbersWords(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testCases := []struct {
+		language string
+		n        string
+		expected string
+	}{
+		{language: "", n: "0", expected: "zero"},
+		{language: "en-US", n: "-15", expected: "minus fifteen"},
+		{language: "en-US", n: "123", expected: "one hundred twenty-three"},
+		{language: "en-US", n: "1000001", expected: "one million one"},
+		{language: "en-US", n: "1000000000000000000000000000000000", expected: "one decillion"},
+		{language: "en-US", n: "1000000000000000000000000000000000000", expected: "1000000000000000000000000000000000000"},
+		{language: "de, fr;q=0.5", n: "21", expected: "vingt et un"},
+		{language: "fr-FR", n: "71", expected: "soixante et onze"},
+		{language: "fr-FR", n: "77", expected: "soixante-dix-sept"},
+		{language: "fr-FR", n: "80", expected: "quatre-vingts"},
+		{language: "fr-FR", n: "81", expected: "quatre-vingt-un"},
+		{language: "fr-FR", n: "91", expected: "quatre-vingt-onze"},
+		{language: "fr-FR", n: "200", expected: "deux cents"},
+		{language: "fr-FR", n: "201", expected: "deux cent un"},
+		{language: "fr-FR", n: "1000", expected: "mille"},
+		{language: "fr-FR", n: "80000", expected: "quatre-vingt mille"},
+		{language: "fr-FR", n: "200000000", expected: "deux cents millions"},
+		{language: "fr-FR", n: "-1001001", expected: "moins un million mille un"},
+	}
+	for _, tc := range testCases {
+		testAPI.Run(tc.language+" "+tc.n, func(ta *tdhttp.TestAPI) {
+			ta.Get("/fizzbuzz/"+tc.n+"?rule=in_range:5000..5001:x&numbers=words", "Accept-Language", tc.language).
+				CmpStatus(http.StatusOK).
+				CmpJSONBody(td.SuperJSONOf(`{"value": $1}`, tc.expected))
+		})
+	}
+}
+
 func TestFizzBuzzInvalidQuery(t *testing.T) {
 	testAPI := tdhttp.NewTestAPI(t, server.New())
 
@@ -63,31 +375,145 @@ func TestFizzBuzzInvalidQuery(t *testing.T) {
 			name:           "invalid int1 query param",
 			url:            "/fizzbuzz?str1=toto&str2=tata&limit=10&int1=-1&int2=3",
 			expectedStatus: http.StatusBadRequest,
-			expectedJSON:   `{"message": "Key: 'FizzBuzzInput.Int1' Error:Field validation for 'Int1' failed on the 'min' tag"}`,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "int1", "value": "-1", "constraint": "min=1", "reason": "int1 should be at least 1"}]})`,
 		},
 		{
 			name:           "invalid int2 query param",
 			url:            "/fizzbuzz?str1=toto&str2=tata&limit=10&int1=1&int2=-3",
 			expectedStatus: http.StatusBadRequest,
-			expectedJSON:   `{"message": "Key: 'FizzBuzzInput.Int2' Error:Field validation for 'Int2' failed on the 'min' tag"}`,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "int2", "value": "-3", "constraint": "min=1", "reason": "int2 should be at least 1"}]})`,
 		},
 		{
 			name:           "invalid limit query param",
 			url:            "/fizzbuzz?str1=toto&str2=tata&limit=-10&int1=1&int2=3",
 			expectedStatus: http.StatusBadRequest,
-			expectedJSON:   `{"message": "Key: 'FizzBuzzInput.Limit' Error:Field validation for 'Limit' failed on the 'min' tag"}`,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "limit", "value": "-10", "constraint": "min=0", "reason": "limit should be at least 0"}]})`,
 		},
 		{
 			name:           "invalid limit query param - should be integer",
 			url:            "/fizzbuzz?str1=toto&str2=tata&limit=string&int1=1&int2=3",
 			expectedStatus: http.StatusBadRequest,
-			expectedJSON:   `{"message": "strconv.ParseInt: parsing \"string\": invalid syntax"}`,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "limit", "value": "string", "constraint": "integer", "reason": "limit should be an integer"}]})`,
 		},
 		{
 			name:           "invalid limit query param - should be lower than threshold",
 			url:            "/fizzbuzz?str1=toto&str2=tata&limit=100000000&int1=1&int2=3",
 			expectedStatus: http.StatusBadRequest,
-			expectedJSON:   `{"message": "limit should be lower than 10000"}`,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "limit", "value": "100000000", "constraint": "max=10000", "reason": "limit should be lower than 10000"}]})`,
+		},
+		{
+			name:           "invalid rule query param - missing word",
+			url:            "/fizzbuzz?rule=3&limit=10",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "rule", "value": "3", "constraint": "format", "reason": "rule \"3\" should be formatted as divisor:word"}]})`,
+		},
+		{
+			name:           "invalid rule query param - divisor should be integer",
+			url:            "/fizzbuzz?rule=three:fizz&limit=10",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "rule", "value": "three:fizz", "constraint": "format", "reason": "rule \"three:fizz\" has an invalid divisor"}]})`,
+		},
+		{
+			name:           "invalid rule query param - divisor should be positive",
+			url:            "/fizzbuzz?rule=3:fizz&rule=0:zero&limit=10",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "rule[1].divisor", "value": "0", "constraint": "min=1", "reason": "rule[1].divisor should be at least 1"}]})`,
+		},
+		{
+			name:           "invalid rule query param - cannot be used along with shorthand",
+			url:            "/fizzbuzz?rule=3:fizz&int1=2&limit=10",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "rule", "value": "3:fizz", "constraint": "excluded_with=int1 int2 str1 str2", "reason": "rule cannot be used along with int1, int2, str1 or str2"}]})`,
+		},
+		{
+			name:           "invalid rule query param - missing predicate argument",
+			url:            "/fizzbuzz?rule=contains_digit:fizz",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "rule", "value": "contains_digit:fizz", "constraint": "format", "reason": "rule \"contains_digit:fizz\" should be formatted as contains_digit:arg:word"}]})`,
+		},
+		{
+			name:           "invalid rule query param - invalid contains_digit argument",
+			url:            "/fizzbuzz?rule=contains_digit:12:fizz",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "rule[0].arg", "value": "12", "constraint": "contains_digit", "reason": "rule[0].arg is not a valid contains_digit argument"}]})`,
+		},
+		{
+			name:           "invalid rule query param - invalid in_range argument",
+			url:            "/fizzbuzz?rule=in_range:5:fizz",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "rule[0].arg", "value": "5", "constraint": "in_range", "reason": "rule[0].arg is not a valid in_range argument"}]})`,
+		},
+		{
+			name:           "invalid step query param",
+			url:            "/fizzbuzz?step=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "step", "value": "0", "constraint": "min=1", "reason": "step should be at least 1"}]})`,
+		},
+		{
+			name:           "invalid combine query param",
+			url:            "/fizzbuzz?combine=last",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "combine", "value": "last", "constraint": "oneof=concat first override", "reason": "combine should be one of concat, first, override"}]})`,
+		},
+		{
+			name:           "invalid combine query param - override without override",
+			url:            "/fizzbuzz?combine=override",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "combine", "value": "override", "constraint": "required_with=override", "reason": "combine=override requires at least one override"}]})`,
+		},
+		{
+			name:           "invalid override query param - cannot be used along with combine=first",
+			url:            "/fizzbuzz?combine=first&override=15:bingo",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "override", "value": "15:bingo", "constraint": "excluded_unless=combine override", "reason": "override cannot be used along with combine=first"}]})`,
+		},
+		{
+			name:           "invalid override query param - divisor should be positive",
+			url:            "/fizzbuzz?override=0:bingo",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "override[0].divisor", "value": "0", "constraint": "min=1", "reason": "override[0].divisor should be at least 1"}]})`,
+		},
+		{
+			name:           "invalid numbers query param",
+			url:            "/fizzbuzz?numbers=octal",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "numbers", "value": "octal", "constraint": "format", "reason": "numbers \"octal\" should be one of decimal, hex, binary, base:N, roman, padded:N or words"}]})`,
+		},
+		{
+			name:           "invalid numbers query param - base out of range",
+			url:            "/fizzbuzz?numbers=base:37",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "numbers", "value": "base:37", "constraint": "format", "reason": "numbers \"base:37\" should be formatted as base:N, N being from 2 to 36"}]})`,
+		},
+		{
+			name:           "invalid numbers query param - padded width out of range",
+			url:            "/fizzbuzz?numbers=padded:65",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "numbers", "value": "padded:65", "constraint": "format", "reason": "numbers \"padded:65\" should be formatted as padded:N, N being from 1 to 64"}]})`,
+		},
+		{
+			name:           "invalid numbers query param - unexpected argument",
+			url:            "/fizzbuzz?numbers=roman:2",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "numbers", "value": "roman:2", "constraint": "format", "reason": "numbers \"roman\" does not take an argument"}]})`,
+		},
+		{
+			name:           "invalid to query param - cannot be used along with limit",
+			url:            "/fizzbuzz?to=10&limit=10",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "to", "value": "10", "constraint": "excluded_with=limit", "reason": "to cannot be used along with limit"}]})`,
+		},
+		{
+			name:           "invalid range - too many terms",
+			url:            "/fizzbuzz?from=-10000&to=10000",
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "limit", "value": "20001", "constraint": "max=10000", "reason": "limit should be lower than 10000"}]})`,
+		},
+		{
+			name:           "invalid range - too many terms, overflowing int",
+			url:            fmt.Sprintf("/fizzbuzz?from=%d&to=%d", math.MinInt, math.MaxInt),
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "limit", "value": "18446744073709551615", "constraint": "max=10000", "reason": "limit should be lower than 10000"}]})`,
 		},
 	}
 	for _, tc := range testCases {
@@ -99,6 +525,262 @@ func TestFizzBuzzInvalidQuery(t *testing.T) {
 	}
 }
 
+func TestFizzBuzzRulesError(t *testing.T) {
+	invalidParams := func(err error) []handlers.InvalidParam {
+		var httpErr *echo.HTTPError
+		td.Require(t).True(errors.As(err, &httpErr))
+		td.Cmp(t, httpErr.Code, http.StatusBadRequest)
+		return httpErr.Internal.(*handlers.ParamsError).Params
+	}
+
+	in := handlers.FizzBuzzInput{Combine: "last"}
+	in.SetDefault()
+	td.Cmp(t, invalidParams(in.ExportNewRules()), td.JSON(`[
+  {"name": "combine", "value": "last", "constraint": "combination", "reason": $1}
+]`, `combine is invalid: unknown combination "last"`))
+
+	in = handlers.FizzBuzzInput{Rules: []fizzbuzz.Rule{{Kind: "is_odd", Word: "odd"}}}
+	in.SetDefault()
+	td.Cmp(t, invalidParams(in.ExportNewRules()), td.JSON(`[
+  {"name": "rule", "value": "is_odd::odd", "constraint": "rule", "reason": $1}
+]`, `rule is invalid: rule is_odd::odd: unknown rule kind "is_odd"`))
+}
+
+func TestFizzBuzzPost(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testCases := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedJSON   string
+	}{
+		{
+			name:           "valid call with rules",
+			body:           `{"rules": [{"divisor": 2, "word": "le"}, {"divisor": 3, "word": "boncoin"}], "limit": 6}`,
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["1", "le", "boncoin", "le", "5", "leboncoin"]}`,
+		},
+		{
+			name:           "valid call with shorthand",
+			body:           `{"int1": 2, "int2": 3, "str1": "le", "str2": "boncoin", "limit": 6}`,
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["1", "le", "boncoin", "le", "5", "leboncoin"]}`,
+		},
+		{
+			name:           "valid call with defaults",
+			body:           `{"limit": 5}`,
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["1", "2", "fizz", "4", "buzz"]}`,
+		},
+		{
+			name:           "valid call with predicates",
+			body:           `{"rules": [{"kind": "is_prime", "word": "p"}, {"kind": "ends_with", "arg": "1", "word": "one"}], "limit": 11}`,
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["one", "p", "p", "4", "p", "6", "p", "8", "9", "10", "pone"]}`,
+		},
+		{
+			name:           "valid call with overrides",
+			body:           `{"overrides": [{"divisor": 15, "word": "bingo"}], "from": 14, "to": 16}`,
+			expectedStatus: http.StatusOK,
+			expectedJSON:   `{"result": ["14", "bingo", "16"]}`,
+		},
+		{
+			name:           "invalid rules - unknown kind",
+			body:           `{"rules": [{"kind": "is_odd", "word": "odd"}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "rule[0].kind", "value": "is_odd", "constraint": "kind", "reason": "rule[0].kind is an unknown rule kind"}]})`,
+		},
+		{
+			name:           "invalid rules - divisor should be positive",
+			body:           `{"rules": [{"divisor": -2, "word": "le"}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedJSON:   `SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "rule[0].divisor", "value": "-2", "constraint": "min=1", "reason": "rule[0].divisor should be at least 1"}]})`,
+		},
+	}
+	for _, tc := range testCases {
+		testAPI.Run(tc.name, func(ta *tdhttp.TestAPI) {
+			ta.Post("/fizzbuzz", strings.NewReader(tc.body), "Content-Type", "application/json").
+				CmpStatus(tc.expectedStatus).
+				CmpJSONBody(td.JSON(tc.expectedJSON))
+		})
+	}
+}
+
+func TestFizzBuzzPagination(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	var next, prev string
+	testAPI.Name("first page").
+		Get("/fizzbuzz?limit=10&page_size=4").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`{"result": ["1", "2", "fizz", "4"], "next_cursor": $1}`,
+			td.Catch(&next, td.NotEmpty())))
+
+	testAPI.Name("second page").
+		Get("/fizzbuzz?limit=10&page_size=4&cursor=" + next).
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`{"result": ["buzz", "fizz", "7", "8"], "next_cursor": $1, "prev_cursor": $2}`,
+			td.Catch(&next, td.NotEmpty()),
+			td.Catch(&prev, td.NotEmpty())))
+
+	testAPI.Name("last page").
+		Get("/fizzbuzz?limit=10&page_size=4&cursor=" + next).
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`{"result": ["fizz", "buzz"], "prev_cursor": NotEmpty()}`))
+
+	testAPI.Name("back to first page").
+		Get("/fizzbuzz?limit=10&page_size=4&cursor=" + prev).
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`{"result": ["1", "2", "fizz", "4"], "next_cursor": NotEmpty()}`))
+
+	testAPI.Name("range beyond the max limit").
+		Get("/fizzbuzz?to=1000000000&page_size=3").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`{"result": ["1", "2", "fizz"], "next_cursor": NotEmpty()}`))
+
+	testAPI.Name("mismatched range").
+		Get("/fizzbuzz?limit=11&page_size=4&cursor=" + next).
+		CmpStatus(http.StatusBadRequest).
+		CmpJSONBody(td.JSON(`SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "cursor", "value": $1, "constraint": "same_parameters", "reason": "cursor does not match the requested parameters"}]})`, next))
+
+	testAPI.Name("mismatched rules").
+		Get("/fizzbuzz?limit=10&page_size=4&int1=2&cursor=" + next).
+		CmpStatus(http.StatusBadRequest).
+		CmpJSONBody(td.JSON(`SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "cursor", "value": $1, "constraint": "same_parameters", "reason": "cursor does not match the requested parameters"}]})`, next))
+
+	tampered := []byte(next)
+	tampered[0] ^= 1
+	testAPI.Name("tampered cursor").
+		Get("/fizzbuzz?limit=10&page_size=4&cursor=" + string(tampered)).
+		CmpStatus(http.StatusBadRequest).
+		CmpJSONBody(td.JSON(`SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "cursor", "value": $1, "constraint": "signed", "reason": "invalid cursor"}]})`, string(tampered)))
+
+	testAPI.Name("page size above max limit").
+		Get("/fizzbuzz?limit=100000&page_size=100000").
+		CmpStatus(http.StatusBadRequest).
+		CmpJSONBody(td.JSON(`SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "page_size", "value": "100000", "constraint": "max=10000", "reason": "page_size should be lower than 10000"}]})`))
+
+	testAPI.Name("pagination along with stream").
+		Get("/fizzbuzz?page_size=10&stream=true").
+		CmpStatus(http.StatusBadRequest).
+		CmpJSONBody(td.JSON(`SuperMapOf({"type": "/problems/invalid-params", "invalid_params": [{"name": "page_size", "value": "10", "constraint": "excluded_with=stream", "reason": "cursor and page_size cannot be used along with stream"}]})`))
+}
+
+func TestFizzBuzzFormats(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	const params = "limit=6&int1=2&int2=3&str1=le&str2=boncoin"
+
+	testAPI.Name("text format").
+		Get("/fizzbuzz?format=text&" + params).
+		CmpStatus(http.StatusOK).
+		CmpHeader(td.SuperMapOf(http.Header{"Content-Type": {"text/plain; charset=UTF-8"}}, nil)).
+		CmpBody("1\nle\nboncoin\nle\n5\nleboncoin\n")
+
+	testAPI.Name("csv accept header").
+		Get("/fizzbuzz?from=4&to=10&step=2&int1=2&int2=3&str1=le&str2=boncoin", "Accept", "text/csv").
+		CmpStatus(http.StatusOK).
+		CmpHeader(td.SuperMapOf(http.Header{"Content-Type": {"text/csv; charset=UTF-8"}}, nil)).
+		CmpBody("n,value\n4,le\n6,leboncoin\n8,le\n10,le\n")
+
+	testAPI.Name("xml accept header with quality").
+		Get("/fizzbuzz?"+params, "Accept", "application/json;q=0.5, application/xml").
+		CmpStatus(http.StatusOK).
+		CmpHeader(td.SuperMapOf(http.Header{"Content-Type": {"application/xml; charset=UTF-8"}}, nil)).
+		CmpBody(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+			`<fizzbuzz><result><term>1</term><term>le</term><term>boncoin</term>` +
+			`<term>le</term><term>5</term><term>leboncoin</term></result></fizzbuzz>`)
+
+	var out map[string]interface{}
+	testAPI.Name("msgpack format").
+		Get("/fizzbuzz?format=msgpack&" + params).
+		CmpStatus(http.StatusOK).
+		CmpHeader(td.SuperMapOf(http.Header{"Content-Type": {handlers.MIMEApplicationMsgpack}}, nil)).
+		CmpBody(td.Smuggle(func(body []byte) error {
+			return msgpack.Unmarshal(body, &out)
+		}, nil))
+	td.Cmp(t, out, map[string]interface{}{
+		"result": []interface{}{"1", "le", "boncoin", "le", "5", "leboncoin"},
+	})
+
+	testAPI.Name("csv beyond int64").
+		Get("/fizzbuzz?format=csv&from=9223372036854775806&to=9223372036854775808").
+		CmpStatus(http.StatusOK).
+		CmpBody("n,value\n9223372036854775806,fizz\n9223372036854775807,9223372036854775807\n" +
+			"9223372036854775808,9223372036854775808\n")
+
+	testAPI.Name("paginated csv").
+		Get("/fizzbuzz?format=csv&page_size=2&" + params).
+		CmpStatus(http.StatusOK).
+		CmpHeader(td.SuperMapOf(http.Header{}, td.MapEntries{handlers.HeaderNextCursor: td.Len(1)})).
+		CmpBody("n,value\n1,1\n2,le\n")
+
+	testAPI.Name("ndjson format").
+		Get("/fizzbuzz?format=ndjson&limit=2").
+		CmpStatus(http.StatusOK).
+		CmpBody("\"1\"\n\"2\"\n")
+
+	testAPI.Name("wildcard accept header").
+		Get("/fizzbuzz?limit=2", "Accept", "text/html, */*;q=0.1").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`{"result": ["1", "2"]}`))
+
+	testAPI.Name("unsupported format").
+		Get("/fizzbuzz?format=yaml").
+		CmpStatus(http.StatusNotAcceptable).
+		CmpJSONBody(td.JSON(`SuperMapOf({"type": "about:blank", "detail": "unsupported format \"yaml\""})`))
+
+	testAPI.Name("unsupported accept header").
+		Get("/fizzbuzz", "Accept", "text/html").
+		CmpStatus(http.StatusNotAcceptable).
+		CmpJSONBody(td.JSON(`SuperMapOf({"type": "about:blank", "detail": "unsupported Accept header \"text/html\""})`))
+}
+
+func TestFizzBuzzBudget(t *testing.T) {
+	defer func(bytes int) { handlers.FizzBuzzMaxBytes = bytes }(handlers.FizzBuzzMaxBytes)
+	handlers.FizzBuzzMaxBytes = 1000
+
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testAPI.Name("under budget").
+		Get("/fizzbuzz?limit=100").
+		CmpStatus(http.StatusOK)
+
+	testAPI.Name("over budget").
+		Get("/fizzbuzz?limit=1000").
+		CmpStatus(http.StatusRequestEntityTooLarge).
+		CmpJSONBody(td.JSON(`SuperMapOf({
+  "type": "about:blank",
+  "status": 413,
+  "detail": "response would weigh about 9140 bytes, above the 1000 bytes budget: lower limit, page_size or words length"
+})`))
+
+	testAPI.Name("long words").
+		Get("/fizzbuzz?limit=100&str1=" + strings.Repeat("a", 100)).
+		CmpStatus(http.StatusRequestEntityTooLarge)
+
+	testAPI.Name("escaped words").
+		Get("/fizzbuzz?limit=100&format=xml&str1=<<<<<<").
+		CmpStatus(http.StatusRequestEntityTooLarge)
+
+	testAPI.Name("stream").
+		Get("/fizzbuzz?limit=1000&stream=true").
+		CmpStatus(http.StatusOK)
+
+	testAPI.Name("paginated").
+		Get("/fizzbuzz?limit=1000&page_size=10").
+		CmpStatus(http.StatusOK)
+
+	testAPI.Name("over budget page").
+		Get("/fizzbuzz?limit=1000&page_size=1000").
+		CmpStatus(http.StatusRequestEntityTooLarge)
+
+	testAPI.Name("periodic").
+		Get("/fizzbuzz?limit=1000&shape=periodic").
+		CmpStatus(http.StatusOK)
+}
+
 func BenchmarkFizzBuzz(b *testing.B) {
 	defer func(old int) { handlers.FizzBuzzMaxLimit = old }(handlers.FizzBuzzMaxLimit)
 	handlers.FizzBuzzMaxLimit = math.MaxInt
@@ -0,0 +1,540 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/labstack/echo/v4"
+)
+
+// graphqlContextKey is the context key of the echo.Context resolvers
+// validate their arguments with.
+type graphqlContextKey struct{}
+
+// graphqlBudgetKey is the context key of the graphqlBudget of a request.
+type graphqlBudgetKey struct{}
+
+// graphqlBudget is what remains of a request's work budget, shared by all
+// its fields, aliases included.
+//
+// Every field costs its number of terms or stats, and at least one.
+// fizzbuzz fields also spend their estimated size from bytes.
+type graphqlBudget struct {
+	mutex sync.Mutex
+	terms uint64
+	size  uint64
+	bytes int
+	err   error
+}
+
+// spend consumes cost from the budget, failing once it is exceeded.
+func (b *graphqlBudget) spend(cost uint64) error {
+	if cost == 0 {
+		cost = 1
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.err == nil && cost > b.terms {
+		b.err = fmt.Errorf("query should compute less than %d terms", FizzBuzzMaxLimit)
+	}
+	if b.err != nil {
+		return b.err
+	}
+	b.terms -= cost
+	return nil
+}
+
+// spendBytes adds size to the estimated size of the response, failing
+// once it exceeds the byte budget.
+func (b *graphqlBudget) spendBytes(size uint64) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.err != nil {
+		return b.err
+	}
+	if size > math.MaxUint64-b.size {
+		b.size = math.MaxUint64
+	} else {
+		b.size += size
+	}
+	if b.size > uint64(b.bytes) {
+		b.err = errors.New(budgetMessage(b.size, b.bytes))
+	}
+	return b.err
+}
+
+// graphqlBudgetOf returns the budget of the request p resolves.
+func graphqlBudgetOf(p graphql.ResolveParams) *graphqlBudget {
+	return p.Context.Value(graphqlBudgetKey{}).(*graphqlBudget)
+}
+
+// spendGraphQL consumes cost from the budget of the request p resolves.
+func spendGraphQL(p graphql.ResolveParams, cost uint64) error {
+	return graphqlBudgetOf(p).spend(cost)
+}
+
+// bigIntType is an integer which may exceed int64, serialized as a string.
+var bigIntType = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "BigInt",
+	Description: "An integer which may exceed int64, serialized as a decimal string. Integers and strings are accepted as input.",
+	Serialize: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case json.Number:
+			return v.String()
+		case *big.Int:
+			return v.String()
+		}
+		return nil
+	},
+	ParseValue: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case string:
+			return parseBigInt(v)
+		case float64:
+			if v == math.Trunc(v) {
+				n, _ := big.NewFloat(v).Int(nil)
+				return n
+			}
+		case int:
+			return big.NewInt(int64(v))
+		}
+		return nil
+	},
+	ParseLiteral: func(value ast.Value) interface{} {
+		switch v := value.(type) {
+		case *ast.IntValue:
+			return parseBigInt(v.Value)
+		case *ast.StringValue:
+			return parseBigInt(v.Value)
+		}
+		return nil
+	},
+})
+
+// parseBigInt returns nil instead of an invalid *big.Int, which graphql
+// reports as an invalid value.
+func parseBigInt(s string) interface{} {
+	if n, ok := new(big.Int).SetString(s, 10); ok {
+		return n
+	}
+	return nil
+}
+
+var ruleInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "RuleInput",
+	Description: "A rule, as a divisor and its word, or a predicate kind, its argument and its word.",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"kind":    &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"arg":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"divisor": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"word":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var termType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Term",
+	Description: "A fizzbuzz term, annotated with the rules its number matches.",
+	Fields: graphql.Fields{
+		"n":       &graphql.Field{Type: graphql.NewNonNull(bigIntType)},
+		"value":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"matched": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+	},
+})
+
+// graphqlFizzBuzz is the source of FizzBuzz objects, whose terms are only
+// computed when requested.
+type graphqlFizzBuzz struct {
+	in    FizzBuzzInput
+	rs    *fizzbuzz.Rules
+	count uint64
+}
+
+var fizzBuzzType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "FizzBuzz",
+	Description: "The terms of a fizzbuzz range.",
+	Fields: graphql.Fields{
+		"count": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return int(p.Source.(graphqlFizzBuzz).count), nil
+			},
+		},
+		"result": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				fb := p.Source.(graphqlFizzBuzz)
+				return fizzBuzzOutput(fb.in, fb.rs, 0, fb.count).Result, nil
+			},
+		},
+		"terms": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(termType))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				fb := p.Source.(graphqlFizzBuzz)
+				terms := make([]FizzBuzzTermOutput, fb.count)
+				n, step := new(big.Int).Set(fb.in.From), big.NewInt(int64(*fb.in.Step))
+				for i := range terms {
+					terms[i] = fizzBuzzTerm(fb.in, fb.rs, n)
+					n.Add(n, step)
+				}
+				return terms, nil
+			},
+		},
+	},
+})
+
+var countType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Count",
+	Description: "The number of hits of a fizzbuzz input.",
+	Fields: graphql.Fields{
+		"key": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"hit": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
+
+var fizzBuzzSchema = newFizzBuzzSchema()
+
+func newFizzBuzzSchema() graphql.Schema {
+	rulesArg := &graphql.ArgumentConfig{
+		Type:        graphql.NewList(graphql.NewNonNull(ruleInputType)),
+		Description: "The rules, fizz and buzz for multiples of 3 and 5 by default.",
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"fizzbuzz": &graphql.Field{
+				Type:        graphql.NewNonNull(fizzBuzzType),
+				Description: "The terms from `from` up to `limit`, like GET /fizzbuzz. Their estimated size counts towards the FIZZBUZZ_GRAPHQL_MAX_BYTES budget of the query.",
+				Args: graphql.FieldConfigArgument{
+					"rules": rulesArg,
+					"limit": &graphql.ArgumentConfig{Type: graphql.Int},
+					"from":  &graphql.ArgumentConfig{Type: bigIntType},
+				},
+				Resolve: resolveFizzBuzz,
+			},
+			"term": &graphql.Field{
+				Type:        graphql.NewNonNull(termType),
+				Description: "The n-th term, like GET /fizzbuzz/{n}.",
+				Args: graphql.FieldConfigArgument{
+					"n":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(bigIntType)},
+					"rules": rulesArg,
+				},
+				Resolve: resolveTerm,
+			},
+			"stats": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(countType))),
+				Description: "The `top` most used inputs whose key contains `filter`, like GET /fizzbuzz/stats.",
+				Args: graphql.FieldConfigArgument{
+					"top":    &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 100},
+					"filter": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: resolveStats,
+			},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: query})
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+// graphqlInput converts rules arguments to a validated FizzBuzzInput.
+func graphqlInput(p graphql.ResolveParams, in FizzBuzzInput) (FizzBuzzInput, *fizzbuzz.Rules, error) {
+	rules, _ := p.Args["rules"].([]interface{})
+	for _, r := range rules {
+		fields := r.(map[string]interface{})
+		rule := fizzbuzz.Rule{Word: fields["word"].(string)}
+		rule.Kind, _ = fields["kind"].(string)
+		rule.Arg, _ = fields["arg"].(string)
+		rule.Divisor, _ = fields["divisor"].(int)
+		in.Rules = append(in.Rules, rule)
+	}
+
+	c := p.Context.Value(graphqlContextKey{}).(echo.Context)
+	if err := validateFizzBuzzInput(c, &in); err != nil {
+		return in, nil, graphqlError(err)
+	}
+
+	rs, err := fizzbuzz.NewRules(in.rules(), in.combination(), in.numbers)
+	if err != nil {
+		c.Logger().Warnf("failed to build rules: %v", err)
+		return in, nil, err
+	}
+	return in, rs, nil
+}
+
+func resolveFizzBuzz(p graphql.ResolveParams) (interface{}, error) {
+	var in FizzBuzzInput
+	if limit, ok := p.Args["limit"].(int); ok {
+		in.Limit = &limit
+	}
+	if from, ok := p.Args["from"].(*big.Int); ok {
+		in.From = from
+	}
+
+	in, rs, err := graphqlInput(p, in)
+	if err != nil {
+		return nil, err
+	}
+
+	count := in.count()
+	if count > uint64(FizzBuzzMaxLimit) {
+		return nil, fmt.Errorf("limit should be lower than %d", FizzBuzzMaxLimit)
+	}
+
+	if err := spendGraphQL(p, count); err != nil {
+		return nil, err
+	}
+
+	size := responseSize(in, rs, 0, count, echo.MIMEApplicationJSON)
+	if err := graphqlBudgetOf(p).spendBytes(size); err != nil {
+		return nil, err
+	}
+
+	// inputs are valid, add this request to fizzbuzz's stats
+	go in.Register()
+
+	return graphqlFizzBuzz{in: in, rs: rs, count: count}, nil
+}
+
+func resolveTerm(p graphql.ResolveParams) (interface{}, error) {
+	in, rs, err := graphqlInput(p, FizzBuzzInput{})
+	if err != nil {
+		return nil, err
+	}
+	if err := spendGraphQL(p, 1); err != nil {
+		return nil, err
+	}
+	return fizzBuzzTerm(in, rs, p.Args["n"].(*big.Int)), nil
+}
+
+func resolveStats(p graphql.ResolveParams) (interface{}, error) {
+	top := p.Args["top"].(int)
+	if top < 0 {
+		return nil, errors.New("top should be positive")
+	}
+	filter, _ := p.Args["filter"].(string)
+
+	values := []interface{}{}
+	for _, count := range fizzBuzzGatherer.OrderedValues() {
+		if len(values) == top {
+			break
+		}
+		if strings.Contains(count.Key, filter) {
+			values = append(values, count)
+		}
+	}
+	if err := spendGraphQL(p, uint64(len(values))); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// graphqlError turns a handler error into a GraphQL error.
+func graphqlError(err error) error {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return errors.New(fmt.Sprint(httpErr.Message))
+	}
+	return err
+}
+
+// GraphQLInput describes the expected input for the GraphQL handler.
+type GraphQLInput struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// GraphQL responds to GET and POST /graphql HTTP requests.
+//
+// It will respond with a 200 HTTP repsonse embedding the GraphQL result of
+// the query, which exposes fizzbuzz(rules, limit, from), term(n, rules)
+// and stats(top, filter).
+//
+// GET requests read the query, operationName and JSON encoded variables
+// from query parameters, POST requests read a GraphQLInput JSON body.
+// Introspection is enabled, see GraphQLPlayground.
+//
+// FizzBuzzMaxLimit applies to the whole query: every field costs its
+// number of terms or stats, and at least one, aliases included. So does
+// FizzBuzzGraphQLMaxBytes to the estimated size of the fizzbuzz fields.
+// Queries beyond either are answered with a 413 status.
+//
+// @Summary GraphQL endpoint.
+// @Description Query fizzbuzz terms and stats with GraphQL.
+// @Tags graphql
+// @Param query query string true "GraphQL query"
+// @Param operationName query string false "GraphQL operation name"
+// @Param variables query string false "GraphQL JSON encoded variables"
+// @Produce json
+// @Success 200 {object} object
+// @Failure 400 {object} handlers.Problem
+// @Failure 413 {object} handlers.Problem
+// @x-response-budget {"env": "FIZZBUZZ_GRAPHQL_MAX_BYTES", "default": 67108864}
+// @Router /graphql [get]
+func GraphQL(c echo.Context) error {
+	var in GraphQLInput
+	if c.Request().Method == http.MethodGet {
+		in.Query = c.QueryParam("query")
+		in.OperationName = c.QueryParam("operationName")
+		if variables := c.QueryParam("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &in.Variables); err != nil {
+				c.Logger().Warnf("failed to parse variables: %v", err)
+				return echo.NewHTTPError(http.StatusBadRequest, "variables should be a JSON object")
+			}
+		}
+	} else if err := c.Bind(&in); err != nil {
+		c.Logger().Warnf("failed to parse body: %v", err)
+		return err
+	}
+
+	if in.Query == "" {
+		c.Logger().Warn("empty GraphQL query")
+		return echo.NewHTTPError(http.StatusBadRequest, "query should not be empty")
+	}
+
+	budget := &graphqlBudget{
+		terms: uint64(FizzBuzzMaxLimit),
+		bytes: FizzBuzzGraphQLMaxBytes,
+	}
+	ctx := context.WithValue(c.Request().Context(), graphqlContextKey{}, c)
+	ctx = context.WithValue(ctx, graphqlBudgetKey{}, budget)
+
+	result := graphql.Do(graphql.Params{
+		Schema:         fizzBuzzSchema,
+		RequestString:  in.Query,
+		VariableValues: in.Variables,
+		OperationName:  in.OperationName,
+		Context:        ctx,
+	})
+	if budget.err != nil {
+		c.Logger().Warnf("GraphQL query over budget: %v", budget.err)
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, budget.err.Error())
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GraphQLPost responds to POST /graphql HTTP requests.
+//
+// It behaves like GraphQL, reading a GraphQLInput JSON body instead of
+// query parameters.
+//
+// @Summary GraphQL endpoint.
+// @Description Query fizzbuzz terms and stats with GraphQL.
+// @Tags graphql
+// @Accept json
+// @Param input body handlers.GraphQLInput true "GraphQL request"
+// @Produce json
+// @Success 200 {object} object
+// @Failure 400 {object} handlers.Problem
+// @Failure 413 {object} handlers.Problem
+// @x-response-budget {"env": "FIZZBUZZ_GRAPHQL_MAX_BYTES", "default": 67108864}
+// @Router /graphql [post]
+func GraphQLPost(c echo.Context) error {
+	return GraphQL(c)
+}
+
+// graphqlPlayground is a self-contained page querying /graphql: it loads
+// no third-party asset.
+const graphqlPlayground = `<!DOCTYPE html>
+<html>
+  <head>
+    <meta charset="utf-8" />
+    <title>fizzbuzz-api GraphQL playground</title>
+    <style>
+      body { margin: 0; font-family: sans-serif; display: flex; height: 100vh; }
+      section { flex: 1; display: flex; flex-direction: column; padding: 8px; }
+      textarea, pre { flex: 1; font-family: monospace; font-size: 13px; margin: 4px 0; }
+      pre { overflow: auto; background: #f5f5f5; padding: 4px; }
+    </style>
+  </head>
+  <body>
+    <section>
+      <label for="query">Query</label>
+      <textarea id="query">{
+  fizzbuzz(limit: 15) {
+    count
+    terms { value }
+  }
+}</textarea>
+      <label for="variables">Variables</label>
+      <textarea id="variables" style="flex: 0.3;">{}</textarea>
+      <div>
+        <button id="run">Run</button>
+        <button id="schema">Schema</button>
+      </div>
+    </section>
+    <section>
+      <label for="result">Result</label>
+      <pre id="result"></pre>
+    </section>
+    <script>
+      const result = document.getElementById("result");
+
+      async function query(body) {
+        try {
+          const res = await fetch("/graphql", {
+            method: "POST",
+            headers: { "Content-Type": "application/json" },
+            body: JSON.stringify(body),
+          });
+          result.textContent = JSON.stringify(await res.json(), null, 2);
+        } catch (err) {
+          result.textContent = String(err);
+        }
+      }
+
+      document.getElementById("run").onclick = function () {
+        let variables;
+        try {
+          variables = JSON.parse(document.getElementById("variables").value || "{}");
+        } catch (err) {
+          result.textContent = "invalid variables: " + err;
+          return;
+        }
+        query({ query: document.getElementById("query").value, variables: variables });
+      };
+
+      document.getElementById("schema").onclick = function () {
+        query({
+          query: "{ __schema { queryType { name } types { name kind description " +
+            "fields { name description args { name type { name kind ofType { name kind } } } " +
+            "type { name kind ofType { name kind } } } } } }",
+        });
+      };
+    </script>
+  </body>
+</html>
+`
+
+// GraphQLPlayground responds to GET /graphql/playground HTTP requests.
+//
+// It will respond with a 200 HTTP repsonse embedding a page running
+// queries against /graphql and browsing its schema by introspection. The
+// page is served from the binary, without any third-party asset.
+//
+// @Summary GraphQL playground.
+// @Description Browse the GraphQL schema and run queries.
+// @Tags graphql
+// @Produce html
+// @Success 200 {string} string
+// @Router /graphql/playground [get]
+func GraphQLPlayground(c echo.Context) error {
+	return c.HTML(http.StatusOK, graphqlPlayground)
+}
@@ -0,0 +1,195 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/handlers"
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"github.com/maxatome/go-testdeep/helpers/tdhttp"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+// graphqlError matches a GraphQL error, whatever its location.
+func graphqlError(message string) td.TestDeep {
+	return td.SuperMapOf(map[string]interface{}{"message": message}, nil)
+}
+
+func TestGraphQL(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testCases := []struct {
+		name     string
+		body     string
+		expected interface{}
+	}{
+		{
+			name: "fizzbuzz result",
+			body: `{"query": "{ fizzbuzz(limit: 5) { count result } }"}`,
+			expected: td.JSON(`{"data": {"fizzbuzz": {
+				"count": 5,
+				"result": ["1", "2", "fizz", "4", "buzz"]
+			}}}`),
+		},
+		{
+			name: "fizzbuzz terms",
+			body: `{
+				"query": "query($from: BigInt) { fizzbuzz(rules: [{divisor: 2, word: \"le\"}, {kind: \"is_prime\", word: \"p\"}], from: $from, limit: 3) { terms { n value matched } } }",
+				"variables": {"from": "1"}
+			}`,
+			expected: td.JSON(`{"data": {"fizzbuzz": {"terms": [
+				{"n": "1", "value": "1", "matched": []},
+				{"n": "2", "value": "lep", "matched": ["2:le", "is_prime:p"]},
+				{"n": "3", "value": "p", "matched": ["is_prime:p"]}
+			]}}}`),
+		},
+		{
+			name: "term",
+			body: `{"query": "{ term(n: \"9223372036854775808\", rules: [{divisor: 2, word: \"le\"}]) { n value matched } }"}`,
+			expected: td.JSON(`{"data": {"term": {
+				"n": "9223372036854775808",
+				"value": "le",
+				"matched": ["2:le"]
+			}}}`),
+		},
+		{
+			name: "invalid rules",
+			body: `{"query": "{ term(n: 3, rules: [{divisor: -2, word: \"le\"}]) { value } }"}`,
+			expected: td.JSON(`{"data": null, "errors": [$1]}`, graphqlError(
+				"rule[0].divisor should be at least 1",
+			)),
+		},
+		{
+			name:     "too many terms",
+			body:     `{"query": "{ fizzbuzz(limit: 10001) { count } }"}`,
+			expected: td.JSON(`{"data": null, "errors": [$1]}`, graphqlError("limit should be lower than 10000")),
+		},
+		{
+			name:     "introspection",
+			body:     `{"query": "{ __schema { queryType { fields { name } } } }"}`,
+			expected: td.JSON(`{"data": {"__schema": {"queryType": {"fields": [{"name": "fizzbuzz"}, {"name": "stats"}, {"name": "term"}]}}}}`),
+		},
+	}
+	for _, tc := range testCases {
+		testAPI.Run(tc.name, func(ta *tdhttp.TestAPI) {
+			ta.Post("/graphql", strings.NewReader(tc.body), "Content-Type", "application/json").
+				CmpStatus(http.StatusOK).
+				CmpJSONBody(tc.expected)
+		})
+	}
+
+	testAPI.Name("GET request").
+		Get("/graphql?query=" + url.QueryEscape("query($n: BigInt!) { term(n: $n) { value } }") +
+			"&variables=" + url.QueryEscape(`{"n": 15}`)).
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`{"data": {"term": {"value": "fizzbuzz"}}}`))
+
+	testAPI.Name("empty query").
+		Get("/graphql").
+		CmpStatus(http.StatusBadRequest).
+		CmpJSONBody(td.JSON(`SuperMapOf({"type": "about:blank", "detail": "query should not be empty"})`))
+
+	testAPI.Name("playground").
+		Get("/graphql/playground").
+		CmpStatus(http.StatusOK).
+		CmpHeader(td.SuperMapOf(http.Header{
+			"Content-Type": {"text/html; charset=UTF-8"},
+		}, nil)).
+		CmpBody(td.All(
+			td.Contains(`fetch("/graphql"`),
+			td.Not(td.Contains("http://")),
+			td.Not(td.Contains("https://")),
+		))
+}
+
+func TestGraphQLBudget(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	aliases := func(n int, field string) string {
+		var sb strings.Builder
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(&sb, "f%d: %s ", i, field)
+		}
+		return `{"query": "{ ` + sb.String() + `}"}`
+	}
+
+	testAPI.Name("within budget").
+		Post("/graphql", strings.NewReader(aliases(2, "fizzbuzz(limit: 5000) { count }")),
+			"Content-Type", "application/json").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`{"data": {"f0": {"count": 5000}, "f1": {"count": 5000}}}`))
+
+	testAPI.Name("aliases over budget").
+		Post("/graphql", strings.NewReader(aliases(50, "fizzbuzz(limit: 10000) { terms { value } }")),
+			"Content-Type", "application/json").
+		CmpStatus(http.StatusRequestEntityTooLarge).
+		CmpJSONBody(td.JSON(`SuperMapOf({"type": "about:blank", "detail": "query should compute less than 10000 terms"})`))
+
+	testAPI.Name("cheap fields cost one").
+		Post("/graphql", strings.NewReader(aliases(10001, "term(n: 3) { value }")),
+			"Content-Type", "application/json").
+		CmpStatus(http.StatusRequestEntityTooLarge)
+}
+
+func TestGraphQLBytesBudget(t *testing.T) {
+	defer func(bytes int) { handlers.FizzBuzzGraphQLMaxBytes = bytes }(handlers.FizzBuzzGraphQLMaxBytes)
+	handlers.FizzBuzzGraphQLMaxBytes = 1000
+
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testAPI.Name("within bytes budget").
+		Post("/graphql", strings.NewReader(`{"query": "{ fizzbuzz(limit: 100) { count } }"}`),
+			"Content-Type", "application/json").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`{"data": {"fizzbuzz": {"count": 100}}}`))
+
+	testAPI.Name("over bytes budget").
+		Post("/graphql", strings.NewReader(`{"query": "{ fizzbuzz(limit: 1000) { count } }"}`),
+			"Content-Type", "application/json").
+		CmpStatus(http.StatusRequestEntityTooLarge).
+		CmpJSONBody(td.JSON(`SuperMapOf({
+  "type": "about:blank",
+  "status": 413,
+  "detail": "response would weigh about 9140 bytes, above the 1000 bytes budget: lower limit, page_size or words length"
+})`))
+
+	testAPI.Name("aliases over bytes budget").
+		Post("/graphql", strings.NewReader(`{"query": "{ a: fizzbuzz(limit: 100) { count } b: fizzbuzz(limit: 100) { count } }"}`),
+			"Content-Type", "application/json").
+		CmpStatus(http.StatusRequestEntityTooLarge)
+
+	testAPI.Name("long words").
+		Post("/graphql", strings.NewReader(`{"query": "{ fizzbuzz(limit: 10, rules: [{divisor: 1, word: \"`+strings.Repeat("a", 1000)+`\"}]) { count } }"}`),
+			"Content-Type", "application/json").
+		CmpStatus(http.StatusRequestEntityTooLarge)
+}
+
+func TestGraphQLStats(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testAPI.Post("/graphql", strings.NewReader(`{"query": "{ fizzbuzz(limit: 9) { count } }"}`),
+		"Content-Type", "application/json").
+		CmpStatus(http.StatusOK)
+
+	testAPI.Get("/fizzbuzz?limit=9").
+		CmpStatus(http.StatusOK)
+
+	// gathering is done asynchronously
+	time.Sleep(100 * time.Millisecond)
+
+	testAPI.Post("/graphql", strings.NewReader(`{"query": "{ stats(top: 1, filter: \"limit=9\") { key hit } }"}`),
+		"Content-Type", "application/json").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`{"data": {"stats": [
+			{"key": "FizzBuzzInput str1=fizz str2=buzz int1=3 int2=5 limit=9", "hit": 2}
+		]}}`))
+
+	testAPI.Post("/graphql", strings.NewReader(`{"query": "{ stats(top: -1) { key } }"}`),
+		"Content-Type", "application/json").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`{"data": null, "errors": [$1]}`, graphqlError("top should be positive")))
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator"
+	"github.com/labstack/echo/v4"
+)
+
+// MIMEApplicationProblemJSON is the content type of error responses, see
+// RFC 7807.
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Problem types, stable identifiers of the kind of error a Problem
+// describes.
+const (
+	// ProblemTypeDefault is the type of problems described by their HTTP
+	// status only.
+	ProblemTypeDefault = "about:blank"
+	// ProblemTypeInvalidParams is the type of problems listing invalid
+	// request parameters.
+	ProblemTypeInvalidParams = "/problems/invalid-params"
+)
+
+// Problem describes an error response, see RFC 7807.
+type Problem struct {
+	Type          string         `json:"type" example:"/problems/invalid-params"`
+	Title         string         `json:"title" example:"Invalid parameters"`
+	Status        int            `json:"status" example:"400"`
+	Detail        string         `json:"detail,omitempty" example:"int1 should be at least 1"`
+	Instance      string         `json:"instance,omitempty" example:"/fizzbuzz?int1=0"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+}
+
+// InvalidParam describes a request parameter the request was rejected for.
+//
+// Name is the query parameter, followed by the index and the field of the
+// element for list parameters such as rule. Constraint is the violated
+// constraint, as a validate tag or the expected type.
+type InvalidParam struct {
+	Name       string `json:"name" example:"int1"`
+	Value      string `json:"value" example:"0"`
+	Constraint string `json:"constraint" example:"min=1"`
+	Reason     string `json:"reason" example:"int1 should be at least 1"`
+
+	// key is the catalog message of Reason, filled with the parameter name
+	// and args, see translate.
+	key  string
+	args []string
+}
+
+// newInvalidParam returns an invalid parameter whose reason is the key
+// catalog message, translated by HTTPErrorHandler.
+func newInvalidParam(name, value, constraint, key string, args ...string) InvalidParam {
+	p := InvalidParam{Name: name, Value: value, Constraint: constraint, key: key, args: args}
+	p.Reason = p.translate(fallbackLocale)
+	return p
+}
+
+// translate returns the reason of the parameter in the language of locale.
+func (p InvalidParam) translate(locale string) string {
+	if p.key == "" {
+		return p.Reason
+	}
+	return translator.translate(locale, p.key, append([]string{p.Name}, p.args...)...)
+}
+
+// ParamsError is the internal error of bad requests caused by invalid
+// parameters.
+type ParamsError struct {
+	Params []InvalidParam
+}
+
+// translate returns a copy of the error whose reasons are in the language
+// of locale.
+func (e *ParamsError) translate(locale string) *ParamsError {
+	params := make([]InvalidParam, len(e.Params))
+	for i, p := range e.Params {
+		p.Reason = p.translate(locale)
+		params[i] = p
+	}
+	return &ParamsError{Params: params}
+}
+
+func (e *ParamsError) Error() string {
+	reasons := make([]string, len(e.Params))
+	for i, p := range e.Params {
+		reasons[i] = p.Reason
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// invalidParams returns a bad request caused by params, its message being
+// their reasons.
+func invalidParams(params ...InvalidParam) *echo.HTTPError {
+	err := &ParamsError{Params: params}
+	return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+}
+
+// ParamName is a validator.TagNameFunc naming fields after their query
+// parameter, or their JSON key if they have none.
+func ParamName(fld reflect.StructField) string {
+	for _, tag := range []string{"query", "json"} {
+		name := strings.SplitN(fld.Tag.Get(tag), ",", 2)[0]
+		if name != "" {
+			return name
+		}
+	}
+	return fld.Name
+}
+
+// NewValidationError converts validator errors into a bad request listing
+// the invalid parameters, named after ParamName.
+func NewValidationError(errs validator.ValidationErrors) *echo.HTTPError {
+	params := make([]InvalidParam, len(errs))
+	for i, fe := range errs {
+		// drop the validated struct name
+		name := fe.Namespace()
+		if i := strings.IndexByte(name, '.'); i >= 0 {
+			name = name[i+1:]
+		}
+
+		constraint := fe.Tag()
+		if fe.Param() != "" {
+			constraint += "=" + fe.Param()
+		}
+
+		key, args := validationMessage(fe.Tag(), fe.Param(), fe.Kind())
+		params[i] = newInvalidParam(name, paramValue(fe.Value()), constraint, key, args...)
+	}
+	return invalidParams(params...)
+}
+
+// paramValue formats a validated value, dereferencing pointers.
+func paramValue(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ""
+		}
+		if s, ok := rv.Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+// validationMessage returns the catalog message explaining the violation
+// of a validate tag by a value of the kind kind, along with its arguments
+// following the parameter name.
+func validationMessage(tag, param string, kind reflect.Kind) (string, []string) {
+	switch tag {
+	case "required", "kind":
+		return tag, nil
+	case "min", "max":
+		if kind == reflect.Slice {
+			return tag + "-items", []string{param}
+		}
+		return tag, []string{param}
+	case "oneof":
+		return tag, []string{strings.Join(strings.Fields(param), ", ")}
+	default:
+		// rule kinds report their invalid argument
+		return "argument", []string{tag}
+	}
+}
+
+// HTTPErrorHandler is the echo.HTTPErrorHandler responding errors as
+// application/problem+json.
+//
+// Errors with a ParamsError as internal error list the invalid parameters,
+// whose reasons are translated according to the Accept-Language header.
+// Other errors are described by their HTTP status and message.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	he := &echo.HTTPError{
+		Code:    http.StatusInternalServerError,
+		Message: http.StatusText(http.StatusInternalServerError),
+	}
+	if !errors.As(err, &he) {
+		c.Logger().Error(err)
+	}
+
+	p := Problem{
+		Type:     ProblemTypeDefault,
+		Title:    http.StatusText(he.Code),
+		Status:   he.Code,
+		Detail:   fmt.Sprint(he.Message),
+		Instance: c.Request().URL.RequestURI(),
+	}
+
+	var paramsErr *ParamsError
+	if errors.As(he.Internal, &paramsErr) {
+		p.Type = ProblemTypeInvalidParams
+		p.Title = "Invalid parameters"
+
+		locale := translator.find(c.Request().Header.Get("Accept-Language"))
+		c.Response().Header().Set("Content-Language", locale)
+
+		paramsErr = paramsErr.translate(locale)
+		p.InvalidParams = paramsErr.Params
+		p.Detail = paramsErr.Error()
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(p.Status)
+	} else {
+		c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+		err = c.JSON(p.Status, p)
+	}
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
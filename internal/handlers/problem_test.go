@@ -0,0 +1,69 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/handlers"
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"github.com/maxatome/go-testdeep/helpers/tdhttp"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestProblem(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testAPI.Name("invalid params").
+		Get("/fizzbuzz?int1=0&int2=0").
+		CmpStatus(http.StatusBadRequest).
+		CmpHeader(td.SuperMapOf(http.Header{
+			"Content-Type": {handlers.MIMEApplicationProblemJSON},
+		}, nil)).
+		CmpJSONBody(td.JSON(`
+{
+  "type": "/problems/invalid-params",
+  "title": "Invalid parameters",
+  "status": 400,
+  "detail": "int1 should be at least 1; int2 should be at least 1",
+  "instance": "/fizzbuzz?int1=0&int2=0",
+  "invalid_params": [
+    {"name": "int1", "value": "0", "constraint": "min=1", "reason": "int1 should be at least 1"},
+    {"name": "int2", "value": "0", "constraint": "min=1", "reason": "int2 should be at least 1"}
+  ]
+}`))
+
+	testAPI.Name("invalid boolean").
+		Get("/fizzbuzz?reverse=maybe").
+		CmpStatus(http.StatusBadRequest).
+		CmpJSONBody(td.JSON(`SuperMapOf({"invalid_params": [
+  {"name": "reverse", "value": "maybe", "constraint": "boolean", "reason": "reverse should be a boolean"}
+]})`))
+
+	testAPI.Name("invalid JSON type").
+		Post("/fizzbuzz", strings.NewReader(`{"limit": "ten"}`), "Content-Type", "application/json").
+		CmpStatus(http.StatusBadRequest).
+		CmpJSONBody(td.JSON(`SuperMapOf({"invalid_params": [
+  {"name": "limit", "value": "string", "constraint": "integer", "reason": "limit should be an integer"}
+]})`))
+
+	testAPI.Name("not found").
+		Get("/unknown").
+		CmpStatus(http.StatusNotFound).
+		CmpHeader(td.SuperMapOf(http.Header{
+			"Content-Type": {handlers.MIMEApplicationProblemJSON},
+		}, nil)).
+		CmpJSONBody(td.JSON(`
+{
+  "type": "about:blank",
+  "title": "Not Found",
+  "status": 404,
+  "detail": "Not Found",
+  "instance": "/unknown"
+}`))
+
+	testAPI.Name("head").
+		Head("/fizzbuzz").
+		CmpStatus(http.StatusMethodNotAllowed).
+		NoBody()
+}
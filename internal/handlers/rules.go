@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
+	"github.com/go-playground/validator"
+)
+
+// ValidateRule is a validator.StructLevelFunc checking a fizzbuzz.Rule's
+// kind and argument, reported under their JSON key.
+func ValidateRule(sl validator.StructLevel) {
+	r := sl.Current().Interface().(fizzbuzz.Rule)
+
+	_, err := r.Predicate()
+	switch name := r.KindName(); {
+	case err == nil:
+	case errors.Is(err, fizzbuzz.ErrUnknownKind):
+		sl.ReportError(r.Kind, "kind", "Kind", "kind", "")
+	case name == fizzbuzz.KindDivisible && r.Arg == "":
+		sl.ReportError(r.Divisor, "divisor", "Divisor", "min", "1")
+	default:
+		sl.ReportError(r.Arg, "arg", "Arg", name, "")
+	}
+}
+
+// isShorthand tells whether the rule can be written with the
+// int1/int2/str1/str2 shorthand.
+func isShorthand(r fizzbuzz.Rule) bool {
+	return r.KindName() == fizzbuzz.KindDivisible && r.Arg == ""
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/labstack/gommon/log"
+)
+
+// FizzBuzzEnvTranslations is the environment variable naming a directory
+// of extra translation catalogs, loaded after the builtin ones.
+const FizzBuzzEnvTranslations = "FIZZBUZZ_TRANSLATIONS_DIR"
+
+// fallbackLocale is the language of messages missing from the requested
+// one.
+const fallbackLocale = "en"
+
+// catalogs are the builtin translation catalogs, in universal-translator
+// JSON format.
+//
+//go:embed translations/*.json
+var catalogs embed.FS
+
+var translator = newTranslator()
+
+// newTranslator loads the builtin catalogs, then the ones of the
+// FizzBuzzEnvTranslations directory, if set. English is the fallback
+// language.
+func newTranslator() *catalog {
+	cat := &catalog{messages: map[string]map[string]message{}}
+	if err := cat.importFS(catalogs, "translations"); err != nil {
+		panic(err)
+	}
+
+	if dir := os.Getenv(FizzBuzzEnvTranslations); dir != "" {
+		if err := cat.importFS(os.DirFS(dir), "."); err != nil {
+			log.Error(
+				"failed to load translations from env",
+				FizzBuzzEnvTranslations,
+				err.Error(),
+			)
+		}
+	}
+	return cat
+}
+
+// catalog holds translated messages by locale, then by key. Its locales
+// are the ones of the imported catalogs.
+//
+// It reads universal-translator catalogs, but unlike it, supports locales
+// which are not compiled in and never panics on missing arguments.
+//
+// It is safe for concurrent use.
+type catalog struct {
+	mutex    sync.RWMutex
+	messages map[string]map[string]message
+}
+
+// catalogEntry is a message of a universal-translator JSON catalog.
+type catalogEntry struct {
+	Locale   string `json:"locale"`
+	Key      string `json:"key"`
+	Trans    string `json:"trans"`
+	Override bool   `json:"override"`
+}
+
+// message is a catalog message, filled with params arguments.
+type message struct {
+	text   string
+	params int
+}
+
+// importFS imports the JSON catalogs of the dir directory of fsys, in
+// lexical order.
+func (cat *catalog) importFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		f, err := fsys.Open(path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		err = cat.importReader(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// importReader imports a JSON catalog, adding the locales it holds
+// messages for. Its entries only replace existing messages when they are
+// flagged as override.
+func (cat *catalog) importReader(r io.Reader) error {
+	var entries []catalogEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return err
+	}
+
+	cat.mutex.Lock()
+	defer cat.mutex.Unlock()
+
+	for _, e := range entries {
+		locale := strings.ToLower(e.Locale)
+		if locale == "" || e.Key == "" {
+			return fmt.Errorf("message %q of locale %q: missing locale or key", e.Key, e.Locale)
+		}
+
+		params := strings.Count(e.Trans, "{")
+		if params != strings.Count(e.Trans, "}") {
+			return fmt.Errorf("message %q of locale %q: unbalanced brackets", e.Key, e.Locale)
+		}
+		for i := 0; i < params; i++ {
+			if !strings.Contains(e.Trans, placeholder(i)) {
+				return fmt.Errorf("message %q of locale %q: missing placeholder %s", e.Key, e.Locale, placeholder(i))
+			}
+		}
+
+		messages, ok := cat.messages[locale]
+		if !ok {
+			messages = map[string]message{}
+			cat.messages[locale] = messages
+		}
+		if _, ok := messages[e.Key]; ok && !e.Override {
+			return fmt.Errorf("message %q of locale %q: already defined", e.Key, e.Locale)
+		}
+		messages[e.Key] = message{text: e.Trans, params: params}
+	}
+	return nil
+}
+
+// placeholder returns the placeholder of the i-th message argument.
+func placeholder(i int) string {
+	return "{" + strconv.Itoa(i) + "}"
+}
+
+// find picks the locale of an Accept-Language header, the fallback one if
+// no accepted language has a catalog.
+func (cat *catalog) find(acceptLanguage string) string {
+	cat.mutex.RLock()
+	defer cat.mutex.RUnlock()
+
+	for _, tag := range parseAccept(acceptLanguage) {
+		tag = strings.ReplaceAll(strings.ToLower(tag), "-", "_")
+		primary, _, _ := strings.Cut(tag, "_")
+		for _, locale := range []string{tag, primary} {
+			if _, ok := cat.messages[locale]; ok {
+				return locale
+			}
+		}
+	}
+	return fallbackLocale
+}
+
+// translate fills the key message of locale with args, falling back to the
+// English message if locale has none or if it expects more args, and to
+// the key if neither is usable.
+func (cat *catalog) translate(locale, key string, args ...string) string {
+	cat.mutex.RLock()
+	defer cat.mutex.RUnlock()
+
+	for _, locale := range []string{locale, fallbackLocale} {
+		msg, ok := cat.messages[locale][key]
+		if !ok || msg.params > len(args) {
+			continue
+		}
+
+		pairs := make([]string, 0, 2*msg.params)
+		for i := 0; i < msg.params; i++ {
+			pairs = append(pairs, placeholder(i), args[i])
+		}
+		return strings.NewReplacer(pairs...).Replace(msg.text)
+	}
+	return key
+}
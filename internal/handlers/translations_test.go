@@ -0,0 +1,245 @@
+package handlers_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/handlers"
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"github.com/maxatome/go-testdeep/helpers/tdhttp"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestTranslations(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testCases := []struct {
+		name           string
+		url            string
+		acceptLanguage string
+		language       string
+		detail         string
+	}{
+		{
+			name:           "french",
+			url:            "/fizzbuzz?int1=0",
+			acceptLanguage: "fr-FR,fr;q=0.9,en;q=0.8",
+			language:       "fr",
+			detail:         "int1 doit être au moins 1",
+		},
+		{
+			name:           "spanish",
+			url:            "/fizzbuzz?combine=last",
+			acceptLanguage: "es",
+			language:       "es",
+			detail:         "combine debe ser uno de concat, first, override",
+		},
+		{
+			name:           "by quality",
+			url:            "/fizzbuzz?limit=100000",
+			acceptLanguage: "de, es;q=0.5, fr;q=0.8",
+			language:       "fr",
+			detail:         "limit doit être inférieur à 10000",
+		},
+		{
+			name:           "bind error",
+			url:            "/fizzbuzz?reverse=maybe",
+			acceptLanguage: "es-MX",
+			language:       "es",
+			detail:         "reverse debe ser un booleano",
+		},
+		{
+			name:           "period",
+			url:            "/fizzbuzz?shape=periodic&int1=10007&int2=10009&limit=1000000000",
+			acceptLanguage: "fr",
+			language:       "fr",
+			detail:         "period doit être inférieur à 10000",
+		},
+		{
+			name:           "stream exclusivity",
+			url:            "/fizzbuzz?page_size=10&stream=true",
+			acceptLanguage: "es",
+			language:       "es",
+			detail:         "cursor y page_size no pueden usarse junto con stream",
+		},
+		{
+			name:           "shorthand exclusivity",
+			url:            "/fizzbuzz?rule=3:fizz&int1=2",
+			acceptLanguage: "fr",
+			language:       "fr",
+			detail:         "rule ne peut pas être utilisé avec int1, int2, str1 ou str2",
+		},
+		{
+			name:           "limit exclusivity",
+			url:            "/fizzbuzz?to=10&limit=10",
+			acceptLanguage: "es",
+			language:       "es",
+			detail:         "to no puede usarse junto con limit",
+		},
+		{
+			name:           "combine exclusivity",
+			url:            "/fizzbuzz?combine=first&override=15:bingo",
+			acceptLanguage: "fr",
+			language:       "fr",
+			detail:         "override ne peut pas être utilisé avec combine=first",
+		},
+		{
+			name:           "override required",
+			url:            "/fizzbuzz?combine=override",
+			acceptLanguage: "es",
+			language:       "es",
+			detail:         "combine=override requiere al menos un override",
+		},
+		{
+			name:           "invalid cursor",
+			url:            "/fizzbuzz?page_size=4&cursor=nope",
+			acceptLanguage: "fr",
+			language:       "fr",
+			detail:         "cursor invalide",
+		},
+		{
+			name:           "term position",
+			url:            "/fizzbuzz/three",
+			acceptLanguage: "fr",
+			language:       "fr",
+			detail:         "n doit être un entier",
+		},
+		{
+			name:           "summary rules",
+			url:            "/fizzbuzz/summary?rule=is_prime:p",
+			acceptLanguage: "es",
+			language:       "es",
+			detail:         "rule solo debe contener reglas de divisibilidad para usar summary",
+		},
+		{
+			name:           "summary overrides",
+			url:            "/fizzbuzz/summary?combine=override&override=15:bingo",
+			acceptLanguage: "fr",
+			language:       "fr",
+			detail:         "summary ne prend pas en charge override",
+		},
+		{
+			name:           "periodic rules",
+			url:            "/fizzbuzz?shape=periodic&rule=is_prime:p",
+			acceptLanguage: "fr",
+			language:       "fr",
+			detail:         "rule ne doit contenir que des règles de divisibilité pour utiliser shape=periodic",
+		},
+		{
+			name:           "numbers",
+			url:            "/fizzbuzz?numbers=octal",
+			acceptLanguage: "es",
+			language:       "es",
+			detail:         `numbers "octal" debe ser decimal, hex, binary, base:N, roman, padded:N o words`,
+		},
+		{
+			name:           "numbers padded",
+			url:            "/fizzbuzz?numbers=padded:0",
+			acceptLanguage: "fr",
+			language:       "fr",
+			detail:         `numbers "padded:0" doit être de la forme padded:N, N allant de 1 à 64`,
+		},
+		{
+			name:           "live exclusivity",
+			url:            "/fizzbuzz/live?format=csv",
+			acceptLanguage: "fr",
+			language:       "fr",
+			detail:         "live ne peut pas être utilisé avec stream, format, cursor, page_size ou shape=periodic",
+		},
+		{
+			name:           "unsupported language",
+			url:            "/fizzbuzz?int1=0",
+			acceptLanguage: "de",
+			language:       "en",
+			detail:         "int1 should be at least 1",
+		},
+		{
+			name:     "no language",
+			url:      "/fizzbuzz?int1=0",
+			language: "en",
+			detail:   "int1 should be at least 1",
+		},
+	}
+	for _, tc := range testCases {
+		testAPI.Run(tc.name, func(ta *tdhttp.TestAPI) {
+			ta.Get(tc.url, "Accept-Language", tc.acceptLanguage).
+				CmpStatus(http.StatusBadRequest).
+				CmpHeader(td.SuperMapOf(http.Header{"Content-Language": {tc.language}}, nil)).
+				CmpJSONBody(td.JSON(`SuperMapOf({"detail": $1, "invalid_params": [SuperMapOf({"reason": $1})]})`, tc.detail))
+		})
+	}
+}
+
+func TestTranslationsHeadersAndBatch(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testAPI.Name("last event id").
+		Get("/fizzbuzz/live", "Accept-Language", "es", "Last-Event-ID", "last").
+		CmpStatus(http.StatusBadRequest).
+		CmpJSONBody(td.JSON(`SuperMapOf({"invalid_params": [
+  {"name": "Last-Event-ID", "value": "last", "constraint": "term-index", "reason": "Last-Event-ID debe ser un índice de término"}
+]})`))
+
+	testAPI.Name("batch limit").
+		PostJSON("/fizzbuzz/batch", []interface{}{map[string]int{"limit": 100000}}, "Accept-Language", "fr").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`[{"status": 400, "error": "limit doit être inférieur à 10000"}]`))
+
+	testAPI.Name("batch exclusivity").
+		PostJSON("/fizzbuzz/batch", []interface{}{map[string]bool{"stream": true}}, "Accept-Language", "es").
+		CmpStatus(http.StatusOK).
+		CmpJSONBody(td.JSON(`[{"status": 400, "error": "las entradas de un batch no pueden usar stream, format, cursor, page_size o shape=periodic"}]`))
+}
+
+func TestTranslationsCatalog(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	importCatalog := func(catalog string) {
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "es.json"), []byte(catalog), 0o600)
+		td.Require(t).CmpNoError(err)
+		td.Require(t).CmpNoError(handlers.ExportImportCatalogs(dir))
+	}
+
+	importCatalog(`[{"locale": "es", "key": "min", "trans": "{0} tiene que ser {1} o más", "override": true}]`)
+	t.Cleanup(func() {
+		importCatalog(`[{"locale": "es", "key": "min", "trans": "{0} debe ser al menos {1}", "override": true}]`)
+	})
+
+	testAPI.Name("overridden message").
+		Get("/fizzbuzz?int1=0", "Accept-Language", "es").
+		CmpStatus(http.StatusBadRequest).
+		CmpJSONBody(td.JSON(`SuperMapOf({"detail": "int1 tiene que ser 1 o más"})`))
+
+	importCatalog(`[
+  {"locale": "nl", "key": "min", "trans": "{0} moet minstens {1} zijn"},
+  {"locale": "es", "key": "oneof", "trans": "{0} debe ser uno de {1} o {2}", "override": true}
+]`)
+	t.Cleanup(func() {
+		importCatalog(`[{"locale": "es", "key": "oneof", "trans": "{0} debe ser uno de {1}", "override": true}]`)
+	})
+
+	testAPI.Name("catalog locale").
+		Get("/fizzbuzz?int1=0", "Accept-Language", "nl-BE").
+		CmpStatus(http.StatusBadRequest).
+		CmpHeader(td.SuperMapOf(http.Header{"Content-Language": {"nl"}}, nil)).
+		CmpJSONBody(td.JSON(`SuperMapOf({"detail": "int1 moet minstens 1 zijn"})`))
+
+	testAPI.Name("missing message").
+		Get("/fizzbuzz?limit=100000", "Accept-Language", "nl").
+		CmpStatus(http.StatusBadRequest).
+		CmpHeader(td.SuperMapOf(http.Header{"Content-Language": {"nl"}}, nil)).
+		CmpJSONBody(td.JSON(`SuperMapOf({"detail": "limit should be lower than 10000"})`))
+
+	testAPI.Name("message expecting more arguments").
+		Get("/fizzbuzz?combine=last", "Accept-Language", "es").
+		CmpStatus(http.StatusBadRequest).
+		CmpJSONBody(td.JSON(`SuperMapOf({"detail": "combine should be one of concat, first, override"})`))
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "it.json"), []byte(`[{"locale": "it", "key": "min", "trans": "{0} {2}"}]`), 0o600)
+	td.Require(t).CmpNoError(err)
+	td.CmpString(t, handlers.ExportImportCatalogs(dir), `it.json: message "min" of locale "it": missing placeholder {1}`)
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/handlers"
+	"github.com/labstack/echo/v4"
+)
+
+// Compute writes to w the body GET /fizzbuzz would respond to query and
+// header, without starting any server.
+//
+// Errors are the message the API would respond with.
+func Compute(w io.Writer, query url.Values, header http.Header) error {
+	e := echo.New()
+	e.Logger.SetOutput(io.Discard)
+
+	// query parameters are bound the same way the API does
+	req, err := http.NewRequest(http.MethodGet, "/fizzbuzz?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	var in handlers.FizzBuzzInput
+	err = (&handlers.Binder{}).Bind(&in, e.NewContext(req, nil))
+	if err == nil {
+		err = handlers.NewFizzBuzzComputer(newValidator(), e.Logger).
+			Compute(w, in, header.Get(echo.HeaderAccept), header.Get("Accept-Language"))
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return errors.New(fmt.Sprint(httpErr.Message))
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package server_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/c-roussel/fizzbuzz-api/internal/server"
+	"github.com/maxatome/go-testdeep/helpers/tdhttp"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestCompute(t *testing.T) {
+	testAPI := tdhttp.NewTestAPI(t, server.New())
+
+	testCases := []struct {
+		name   string
+		query  url.Values
+		header http.Header
+	}{
+		{
+			name:  "default values",
+			query: url.Values{},
+		},
+		{
+			name:  "csv",
+			query: url.Values{"limit": {"15"}, "format": {"csv"}},
+		},
+		{
+			name:  "text",
+			query: url.Values{"limit": {"15"}, "format": {"text"}},
+		},
+		{
+			name:   "xml",
+			query:  url.Values{"limit": {"15"}},
+			header: http.Header{"Accept": {"application/xml"}},
+		},
+		{
+			name:  "rules",
+			query: url.Values{"rule": {"2:le", "ends_with:7:seven"}, "from": {"9223372036854775806"}, "to": {"9223372036854775808"}},
+		},
+		{
+			name:  "stream",
+			query: url.Values{"limit": {"2000"}, "stream": {"true"}},
+		},
+		{
+			name:  "periodic msgpack",
+			query: url.Values{"shape": {"periodic"}, "format": {"msgpack"}},
+		},
+		{
+			name:  "page",
+			query: url.Values{"page_size": {"3"}, "format": {"text"}},
+		},
+		{
+			name:   "words",
+			query:  url.Values{"limit": {"3"}, "numbers": {"words"}},
+			header: http.Header{"Accept-Language": {"fr-FR"}},
+		},
+	}
+	for _, tc := range testCases {
+		testAPI.Run(tc.name, func(ta *tdhttp.TestAPI) {
+			var buf bytes.Buffer
+			td.Require(ta.T()).CmpNoError(server.Compute(&buf, tc.query, tc.header))
+
+			var headers []interface{}
+			for k, v := range tc.header {
+				headers = append(headers, k, v[0])
+			}
+			ta.Get("/fizzbuzz?"+tc.query.Encode(), headers...).
+				CmpStatus(http.StatusOK).
+				CmpBody(buf.String())
+		})
+	}
+
+	err := server.Compute(&bytes.Buffer{}, url.Values{"limit": {"-1"}}, nil)
+	td.CmpString(t, err, "limit should be at least 0")
+
+	err = server.Compute(&bytes.Buffer{}, url.Values{"format": {"yaml"}}, nil)
+	td.CmpString(t, err, `unsupported format "yaml"`)
+}
@@ -0,0 +1,16 @@
+package server
+
+import (
+	"github.com/c-roussel/fizzbuzz-api/internal/fizzbuzzpb"
+	"github.com/c-roussel/fizzbuzz-api/internal/handlers"
+	"github.com/labstack/gommon/log"
+	"google.golang.org/grpc"
+)
+
+// NewGRPC returns the gRPC server of the fizzbuzz service, validating
+// requests the same way as the echo webserver.
+func NewGRPC() *grpc.Server {
+	s := grpc.NewServer()
+	fizzbuzzpb.RegisterFizzBuzzServiceServer(s, handlers.NewFizzBuzzService(newValidator(), log.New("grpc")))
+	return s
+}
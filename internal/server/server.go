@@ -1,10 +1,12 @@
 package server
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/c-roussel/fizzbuzz-api/internal/handlers"
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
 	"github.com/go-playground/validator"
 	"github.com/labstack/echo-contrib/prometheus"
 	"github.com/labstack/echo/v4"
@@ -18,14 +20,29 @@ type CustomValidator struct {
 	validator *validator.Validate
 }
 
-// Validate checks the passed variable's validate tags.
+// Validate checks the passed variable's validate tags, reporting the
+// invalid parameters.
 func (cv *CustomValidator) Validate(i interface{}) error {
-	if err := cv.validator.Struct(i); err != nil {
+	err := cv.validator.Struct(i)
+	var errs validator.ValidationErrors
+	if errors.As(err, &errs) {
+		return handlers.NewValidationError(errs)
+	}
+	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 	return nil
 }
 
+// newValidator returns the CustomValidator shared by the HTTP and gRPC
+// servers.
+func newValidator() *CustomValidator {
+	v := validator.New()
+	v.RegisterTagNameFunc(handlers.ParamName)
+	v.RegisterStructValidation(handlers.ValidateRule, fizzbuzz.Rule{})
+	return &CustomValidator{validator: v}
+}
+
 // urlSkipper middleware ignores metrics on some route
 func urlSkipper(c echo.Context) bool {
 	return strings.HasPrefix(c.Path(), "/mon")
@@ -43,14 +60,42 @@ func New() *echo.Echo {
 	p.MetricsPath = "/mon/metrics"
 	p.Use(e)
 
-	// Default data validation
-	e.Validator = &CustomValidator{validator: validator.New()}
+	// Default data binding and validation, errors are responded as
+	// application/problem+json
+	e.Binder = &handlers.Binder{}
+	e.Validator = newValidator()
+	e.HTTPErrorHandler = handlers.HTTPErrorHandler
 
 	// Routes
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 	e.GET("/mon/ping", handlers.Ping)
 	e.GET("/fizzbuzz", handlers.FizzBuzz)
+	e.POST("/fizzbuzz", handlers.FizzBuzzPost)
 	e.GET("/fizzbuzz/stats", handlers.FizzBuzzStats)
+	e.GET("/fizzbuzz/summary", handlers.FizzBuzzSummary)
+	e.POST("/fizzbuzz/infer", handlers.FizzBuzzInfer)
+	e.POST("/fizzbuzz/batch", handlers.FizzBuzzBatch)
+	e.GET("/fizzbuzz/:n", handlers.FizzBuzzTerm)
+	e.GET("/graphql", handlers.GraphQL)
+	e.POST("/graphql", handlers.GraphQLPost)
+	e.GET("/graphql/playground", handlers.GraphQLPlayground)
+
+	// Admin routes require the FIZZBUZZ_ADMIN_TOKEN bearer token
+	admin := e.Group("/admin", handlers.AdminAuth())
+	admin.DELETE("/stats", handlers.AdminStatsReset)
+	admin.GET("/stats/key", handlers.AdminStatsGet)
+	admin.DELETE("/stats/key", handlers.AdminStatsDelete)
+	admin.GET("/stats/denylist", handlers.AdminStatsDenylist)
+	admin.POST("/stats/denylist", handlers.AdminStatsDeny)
+	admin.DELETE("/stats/denylist", handlers.AdminStatsAllow)
+
+	// Live streams end along with the server
+	live := handlers.NewFizzBuzzLive()
+	e.GET("/fizzbuzz/live", live.Handle)
+	e.Server.RegisterOnShutdown(live.Shutdown)
+
+	// The responses cache is warmed along with statistics
+	e.Server.RegisterOnShutdown(handlers.WarmFizzBuzzCache())
 
 	return e
 }
@@ -11,28 +11,40 @@ import (
 //  - Notify a key hit using Gatberer.Hit(key)
 //  - Retrieve the different hits using Gatherer.Values()
 //  - Reset the hits using Gatherer.Reset()
+//  - Detect changes using Gatherer.Version()
+//  - Never count a key using Gatherer.Deny(key)
+//  - Check for a denied key using Gatherer.IsDenied(key)
 type Gatherer struct {
 	mutex    sync.Mutex
 	registry map[string]int
+	denied   map[string]struct{}
+	version  uint64
 }
 
 // Count reprents the number of hits a key encountered.
 type Count struct {
-	Key string `json:"key"`
-	Hit int    `json:"hit"`
+	Key string `json:"key" xml:"key"`
+	Hit int    `json:"hit" xml:"hit"`
 }
 
 // NewGatherer will spawn a Gatherer instance.
 func NewGatherer() *Gatherer {
-	return &Gatherer{registry: make(map[string]int)}
+	return &Gatherer{
+		registry: make(map[string]int),
+		denied:   make(map[string]struct{}),
+	}
 }
 
-// Hit acknowledges a key hit.
+// Hit acknowledges a key hit, unless the key is denied.
 func (g *Gatherer) Hit(key string) {
 	g.mutex.Lock()
 	defer g.mutex.Unlock()
 
+	if _, ok := g.denied[key]; ok {
+		return
+	}
 	g.registry[key]++
+	g.version++
 }
 
 // Values gathers the hit keys as a slice of Count.
@@ -48,18 +60,101 @@ func (g *Gatherer) Values() []Count {
 }
 
 // OrderedValues gathers the hit keys as an descending ordered slice of Count.
+//
+// Keys with the same number of hits are sorted alphabetically, so that the
+// order only changes along with the version.
 func (g *Gatherer) OrderedValues() []Count {
 	values := g.Values()
-	sort.SliceStable(values, func(i, j int) bool {
-		return values[i].Hit > values[j].Hit
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Hit != values[j].Hit {
+			return values[i].Hit > values[j].Hit
+		}
+		return values[i].Key < values[j].Key
 	})
 	return values
 }
 
+// Version returns a counter incremented on every change of the hits.
+func (g *Gatherer) Version() uint64 {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	return g.version
+}
+
 // Reset trashes all previous hits.
 func (g *Gatherer) Reset() {
 	g.mutex.Lock()
 	defer g.mutex.Unlock()
 
 	g.registry = make(map[string]int)
+	g.version++
+}
+
+// Get returns the number of hits of a key, and whether it was hit at all.
+func (g *Gatherer) Get(key string) (int, bool) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	hit, ok := g.registry[key]
+	return hit, ok
+}
+
+// Delete trashes the hits of a key, reporting whether it was hit at all.
+func (g *Gatherer) Delete(key string) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if _, ok := g.registry[key]; !ok {
+		return false
+	}
+	delete(g.registry, key)
+	g.version++
+	return true
+}
+
+// Deny stops counting a key, trashing its previous hits.
+func (g *Gatherer) Deny(key string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.denied[key] = struct{}{}
+	if _, ok := g.registry[key]; ok {
+		delete(g.registry, key)
+		g.version++
+	}
+}
+
+// Allow counts a denied key again, reporting whether it was denied.
+func (g *Gatherer) Allow(key string) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if _, ok := g.denied[key]; !ok {
+		return false
+	}
+	delete(g.denied, key)
+	return true
+}
+
+// IsDenied tells whether a key is denied.
+func (g *Gatherer) IsDenied(key string) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	_, ok := g.denied[key]
+	return ok
+}
+
+// Denied returns the alphabetically sorted denied keys.
+func (g *Gatherer) Denied() []string {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	keys := make([]string, 0, len(g.denied))
+	for key := range g.denied {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
@@ -0,0 +1,146 @@
+package fizzbuzz
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Combination modes, telling which word replaces a number matching several
+// rules.
+const (
+	// CombineConcat concatenates the matching words, in rule order.
+	CombineConcat = "concat"
+	// CombineFirst only keeps the first matching word.
+	CombineFirst = "first"
+	// CombineOverride replaces the numbers matching several rules by the
+	// word of their first multiple-of override, and concatenates the
+	// matching words otherwise.
+	CombineOverride = "override"
+)
+
+// Override replaces the numbers matching several rules which are multiples
+// of Divisor by Word.
+//
+// On query parameters, an override is written as `divisor:word`, e.g.
+// `15:bingo`.
+type Override struct {
+	Divisor int    `json:"divisor" validate:"min=1"`
+	Word    string `json:"word"`
+}
+
+// UnmarshalParam parses a `divisor:word` query parameter.
+//
+// It implements echo.BindUnmarshaler.
+func (o *Override) UnmarshalParam(param string) error {
+	head, tail, found := strings.Cut(param, ":")
+	if !found {
+		return invalid(ErrInvalidCombination, fmt.Errorf("override %q should be formatted as divisor:word", param))
+	}
+
+	d, err := strconv.Atoi(head)
+	if err != nil {
+		return invalid(ErrInvalidCombination, fmt.Errorf("override %q has an invalid divisor: %w", param, err))
+	}
+
+	o.Divisor, o.Word = d, tail
+	return nil
+}
+
+// String formats the override the same way it is read from query
+// parameters.
+func (o Override) String() string {
+	return strconv.Itoa(o.Divisor) + ":" + o.Word
+}
+
+// Combination describes how the words of a number matching several rules
+// are combined.
+//
+// Its zero value concatenates words without separator, in rule order.
+type Combination struct {
+	// Mode is one of CombineConcat, CombineFirst or CombineOverride, an
+	// empty one concatenating words.
+	Mode      string
+	Separator string
+	// Reverse combines words from the last matching rule.
+	Reverse   bool
+	Overrides []Override
+}
+
+// IsDefault tells whether the combination is the historical str1+str2 one.
+func (comb Combination) IsDefault() bool {
+	return (comb.Mode == "" || comb.Mode == CombineConcat) &&
+		comb.Separator == "" && !comb.Reverse && len(comb.Overrides) == 0
+}
+
+// String describes a non-default combination for statistics keys.
+func (comb Combination) String() string {
+	s := fmt.Sprintf("combine=%s separator=%q reverse=%t", comb.Mode, comb.Separator, comb.Reverse)
+	for _, o := range comb.Overrides {
+		s += " override=" + o.String()
+	}
+	return s
+}
+
+// validate checks the combination's mode and overrides.
+func (comb Combination) validate() error {
+	switch comb.Mode {
+	case "", CombineConcat, CombineFirst, CombineOverride:
+	default:
+		return invalid(ErrInvalidCombination, fmt.Errorf("unknown combination %q", comb.Mode))
+	}
+
+	for _, o := range comb.Overrides {
+		if o.Divisor < 1 {
+			return invalid(ErrInvalidCombination, fmt.Errorf("override %s: divisor should be at least 1", o))
+		}
+	}
+	return nil
+}
+
+// override returns the word of the first override v is a multiple of.
+func (comb Combination) override(v int) (string, bool) {
+	for _, o := range comb.Overrides {
+		if v%o.Divisor == 0 {
+			return o.Word, true
+		}
+	}
+	return "", false
+}
+
+// overrideBig returns the word of the first override v is a multiple of,
+// with arbitrary precision.
+func (comb Combination) overrideBig(v *big.Int) (string, bool) {
+	rem := new(big.Int)
+	for _, o := range comb.Overrides {
+		if rem.Rem(v, big.NewInt(int64(o.Divisor))).Sign() == 0 {
+			return o.Word, true
+		}
+	}
+	return "", false
+}
+
+// Combine builds the word replacing a number from its matching words, in
+// rule order.
+func (comb Combination) Combine(words []string) string {
+	if len(words) == 1 {
+		return words[0]
+	}
+
+	if comb.Mode == CombineFirst {
+		if comb.Reverse {
+			return words[len(words)-1]
+		}
+		return words[0]
+	}
+
+	if comb.Reverse {
+		reversed := make([]string, len(words))
+		for i, w := range words {
+			reversed[len(words)-1-i] = w
+		}
+		words = reversed
+	}
+	return strings.Join(words, comb.Separator)
+}
@@ -0,0 +1,22 @@
+// Package fizzbuzz computes customizable fizzbuzz sequences.
+//
+// Rules replace the numbers matching their predicate by their word, the
+// words of numbers matching several rules being combined according to a
+// Combination, and the numbers matching none being rendered according to
+// Numbers:
+//
+//	rules, err := fizzbuzz.NewRules([]fizzbuzz.Rule{
+//		{Divisor: 3, Word: "fizz"},
+//		{Divisor: 5, Word: "buzz"},
+//	}, fizzbuzz.Combination{}, fizzbuzz.Numbers{})
+//
+// A Generator then iterates over the terms of a range, which may exceed
+// int64:
+//
+//	g, err := fizzbuzz.NewGenerator(rules, big.NewInt(1), big.NewInt(100), 1)
+//	for term, ok := g.Next(); ok; term, ok = g.Next() {
+//		fmt.Println(term)
+//	}
+//
+// Validation errors are told apart with errors.Is, see ErrInvalidRule.
+package fizzbuzz
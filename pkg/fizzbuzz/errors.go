@@ -0,0 +1,46 @@
+package fizzbuzz
+
+import "errors"
+
+// Validation errors, told apart with errors.Is. Returned errors keep their
+// own message, describing the invalid value.
+var (
+	// ErrUnknownKind is returned for rules of an unregistered kind.
+	ErrUnknownKind = errors.New("unknown rule kind")
+	// ErrInvalidRule is returned for rules whose argument or notation is
+	// invalid, e.g. a divisor lower than 1.
+	ErrInvalidRule = errors.New("invalid rule")
+	// ErrInvalidCombination is returned for unknown combination modes and
+	// invalid overrides.
+	ErrInvalidCombination = errors.New("invalid combination")
+	// ErrInvalidNumbers is returned for unknown or malformed number
+	// rendering modes.
+	ErrInvalidNumbers = errors.New("invalid numbers")
+	// ErrInvalidRange is returned for ranges without bounds or with a step
+	// lower than 1.
+	ErrInvalidRange = errors.New("invalid range")
+)
+
+// validationError is an error matching one of the validation errors,
+// while keeping its own message.
+type validationError struct {
+	kind error
+	err  error
+}
+
+// invalid tags err as a kind validation error.
+func invalid(kind, err error) error {
+	return &validationError{kind: kind, err: err}
+}
+
+func (e *validationError) Error() string {
+	return e.err.Error()
+}
+
+func (e *validationError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *validationError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,225 @@
+package fizzbuzz_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/c-roussel/fizzbuzz-api/pkg/fizzbuzz"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+var classicRules = []fizzbuzz.Rule{{Divisor: 3, Word: "fizz"}, {Divisor: 5, Word: "buzz"}}
+
+func newRules(t *testing.T, rules []fizzbuzz.Rule, comb fizzbuzz.Combination) *fizzbuzz.Rules {
+	rs, err := fizzbuzz.NewRules(rules, comb, fizzbuzz.Numbers{})
+	td.Require(t).CmpNoError(err)
+	return rs
+}
+
+func newGenerator(t *testing.T, rs *fizzbuzz.Rules, from, to string, step int) *fizzbuzz.Generator {
+	bigFrom, _ := new(big.Int).SetString(from, 10)
+	bigTo, _ := new(big.Int).SetString(to, 10)
+	g, err := fizzbuzz.NewGenerator(rs, bigFrom, bigTo, step)
+	td.Require(t).CmpNoError(err)
+	return g
+}
+
+func collect(g *fizzbuzz.Generator) []string {
+	var terms []string
+	for term, ok := g.Next(); ok; term, ok = g.Next() {
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+func TestGenerator(t *testing.T) {
+	g := newGenerator(t, newRules(t, classicRules, fizzbuzz.Combination{}), "1", "15", 1)
+	td.Cmp(t, g.Count(), uint64(15))
+	td.Cmp(t, collect(g), []string{
+		"1", "2", "fizz", "4", "buzz", "fizz", "7", "8", "fizz", "buzz", "11", "fizz", "13", "14", "fizzbuzz",
+	})
+
+	_, ok := g.Next()
+	td.CmpFalse(t, ok, "range is over")
+
+	g.Seek(12)
+	td.Cmp(t, g.Value(), big.NewInt(13))
+	td.Cmp(t, collect(g), []string{"13", "14", "fizzbuzz"})
+
+	rs := newRules(t, []fizzbuzz.Rule{{Divisor: 2, Word: "le"}, {Kind: fizzbuzz.KindEndsWith, Arg: "7", Word: "seven"}},
+		fizzbuzz.Combination{Separator: "-", Reverse: true})
+	g = newGenerator(t, rs, "9223372036854775806", "9223372036854775810", 2)
+	td.Cmp(t, g.Count(), uint64(3))
+	td.Cmp(t, collect(g), []string{"le", "le", "le"})
+	td.Cmp(t, g.Term(big.NewInt(17)), "seven")
+	td.Cmp(t, g.Term(big.NewInt(1000007)), "seven")
+	td.Cmp(t, g.Term(big.NewInt(27)), "seven")
+	td.Cmp(t, g.Term(big.NewInt(-27)), "seven")
+	td.Cmp(t, g.Term(big.NewInt(1)), "1")
+	td.Cmp(t, g.Term(big.NewInt(-4)), "le")
+
+	g = newGenerator(t, rs, "5", "1", 1)
+	td.Cmp(t, g.Count(), uint64(0))
+	td.CmpNil(t, collect(g))
+}
+
+func TestGeneratorWriteTo(t *testing.T) {
+	g := newGenerator(t, newRules(t, classicRules, fizzbuzz.Combination{}), "1", "5", 1)
+
+	var sb strings.Builder
+	n, err := g.WriteTo(&sb)
+	td.CmpNoError(t, err)
+	td.Cmp(t, sb.String(), "1\n2\nfizz\n4\nbuzz\n")
+	td.Cmp(t, n, int64(sb.Len()))
+}
+
+func TestGeneratorSize(t *testing.T) {
+	rs := newRules(t, classicRules, fizzbuzz.Combination{})
+	g := newGenerator(t, rs, "1", "15", 1)
+	td.Cmp(t, g.Size(15, nil), uint64(70))
+	td.Cmp(t, g.Size(100, nil), uint64(70), "capped to the range")
+	td.Cmp(t, g.Size(0, nil), uint64(0))
+
+	quoted := func(s string) int { return len(s) + 2 }
+	td.Cmp(t, g.Size(15, quoted), uint64(70+10*4))
+
+	for _, tc := range []struct {
+		from, to string
+		step     int
+		numbers  string
+	}{
+		{from: "1", to: "1000", step: 1, numbers: "decimal"},
+		{from: "-500", to: "500", step: 7, numbers: "hex"},
+		{from: "1", to: "3999", step: 1, numbers: "roman"},
+		{from: "1", to: "1000", step: 3, numbers: "words"},
+		{from: "9223372036854775000", to: "9223372036854777000", step: 5, numbers: "decimal"},
+	} {
+		numbers, err := fizzbuzz.ParseNumbers(tc.numbers, "en")
+		td.Require(t).CmpNoError(err)
+		rs, err := fizzbuzz.NewRules(classicRules, fizzbuzz.Combination{}, numbers)
+		td.Require(t).CmpNoError(err)
+
+		g := newGenerator(t, rs, tc.from, tc.to, tc.step)
+		size := g.Size(g.Count(), nil)
+		td.Cmp(t, size, td.Gte(uint64(len(strings.Join(collect(g), "")))),
+			"%s..%s step %d %s", tc.from, tc.to, tc.step, tc.numbers)
+	}
+
+	g = newGenerator(t, rs, "1", "18446744073709551615", 1)
+	td.Cmp(t, g.Size(g.Count(), nil), uint64(math.MaxUint64), "saturated")
+}
+
+func TestGeneratorPeriod(t *testing.T) {
+	rs := newRules(t, classicRules, fizzbuzz.Combination{})
+	td.Cmp(t, newGenerator(t, rs, "1", "100", 1).Period(), big.NewInt(15))
+	td.Cmp(t, newGenerator(t, rs, "1", "100", 3).Period(), big.NewInt(5))
+
+	rs = newRules(t, classicRules, fizzbuzz.Combination{
+		Mode:      fizzbuzz.CombineOverride,
+		Overrides: []fizzbuzz.Override{{Divisor: 45, Word: "bingo"}},
+	})
+	td.Cmp(t, newGenerator(t, rs, "1", "100", 1).Period(), big.NewInt(45))
+
+	rs = newRules(t, []fizzbuzz.Rule{{Kind: fizzbuzz.KindIsPrime, Word: "p"}}, fizzbuzz.Combination{})
+	td.CmpNil(t, newGenerator(t, rs, "1", "100", 1).Period())
+}
+
+func TestGeneratorSummary(t *testing.T) {
+	g := newGenerator(t, newRules(t, classicRules, fizzbuzz.Combination{}), "1", "15", 1)
+	s, err := g.Summary()
+	td.Require(t).CmpNoError(err)
+	td.Cmp(t, s, fizzbuzz.Summary{
+		Count: big.NewInt(15),
+		Words: []fizzbuzz.SummaryCount{
+			{Rules: []int{0}, Word: "fizz", Count: big.NewInt(4), First: big.NewInt(3), Density: 4. / 15},
+			{Rules: []int{1}, Word: "buzz", Count: big.NewInt(2), First: big.NewInt(5), Density: 2. / 15},
+			{Rules: []int{0, 1}, Word: "fizzbuzz", Count: big.NewInt(1), First: big.NewInt(15), Density: 1. / 15},
+		},
+		Numbers: fizzbuzz.SummaryCount{Count: big.NewInt(8), First: big.NewInt(1), Density: 8. / 15},
+	})
+
+	g = newGenerator(t, newRules(t, classicRules, fizzbuzz.Combination{}), "1", "1000000000000000000000000000000", 1)
+	td.Cmp(t, g.Len().String(), "1000000000000000000000000000000")
+	td.Cmp(t, g.Count(), uint64(math.MaxUint64), "saturated")
+	s, err = g.Summary()
+	td.Require(t).CmpNoError(err)
+	td.Cmp(t, s.Words[2].Count.String(), "66666666666666666666666666666")
+
+	g = newGenerator(t, newRules(t, []fizzbuzz.Rule{{Kind: fizzbuzz.KindIsPrime, Word: "p"}}, fizzbuzz.Combination{}), "1", "15", 1)
+	_, err = g.Summary()
+	td.CmpTrue(t, errors.Is(err, fizzbuzz.ErrInvalidRule))
+
+	g = newGenerator(t, newRules(t, classicRules, fizzbuzz.Combination{
+		Mode:      fizzbuzz.CombineOverride,
+		Overrides: []fizzbuzz.Override{{Divisor: 45, Word: "bingo"}},
+	}), "1", "15", 1)
+	_, err = g.Summary()
+	td.CmpTrue(t, errors.Is(err, fizzbuzz.ErrInvalidCombination))
+}
+
+func TestGCD(t *testing.T) {
+	td.Cmp(t, fizzbuzz.GCD(12, 18), 6)
+	td.Cmp(t, fizzbuzz.GCD(0, 7), 7)
+	td.Cmp(t, fizzbuzz.GCD(7, 0), 7)
+}
+
+func TestRules(t *testing.T) {
+	rs := newRules(t, classicRules, fizzbuzz.Combination{
+		Mode:      fizzbuzz.CombineOverride,
+		Overrides: []fizzbuzz.Override{{Divisor: 45, Word: "bingo"}},
+	})
+	td.Cmp(t, rs.Term(big.NewInt(45)), "bingo")
+	td.Cmp(t, rs.Term(big.NewInt(30)), "fizzbuzz")
+	td.Cmp(t, rs.Matching(big.NewInt(30)), []int{0, 1})
+	td.Cmp(t, rs.Divisors(), []*big.Int{big.NewInt(3), big.NewInt(5)})
+	td.Cmp(t, rs.Period(), big.NewInt(15))
+
+	rs = newRules(t, []fizzbuzz.Rule{{Kind: fizzbuzz.KindIsPrime, Word: "p"}}, fizzbuzz.Combination{})
+	td.CmpNil(t, rs.Divisors())
+	td.CmpNil(t, rs.Period())
+
+	// without any rule, every term is a number
+	rs = newRules(t, nil, fizzbuzz.Combination{})
+	td.CmpNil(t, rs.Period())
+	td.Cmp(t, rs.Term(big.NewInt(15)), "15")
+	td.Cmp(t, collect(newGenerator(t, rs, "1", "3", 1)), []string{"1", "2", "3"})
+}
+
+func TestErrors(t *testing.T) {
+	numbers := fizzbuzz.Numbers{}
+
+	_, err := fizzbuzz.NewRules([]fizzbuzz.Rule{{Kind: "is_odd", Word: "odd"}}, fizzbuzz.Combination{}, numbers)
+	td.CmpString(t, err, `rule is_odd::odd: unknown rule kind "is_odd"`)
+	td.CmpTrue(t, errors.Is(err, fizzbuzz.ErrUnknownKind))
+	td.CmpFalse(t, errors.Is(err, fizzbuzz.ErrInvalidRule))
+
+	_, err = fizzbuzz.NewRules([]fizzbuzz.Rule{{Divisor: 0, Word: "zero"}}, fizzbuzz.Combination{}, numbers)
+	td.CmpString(t, err, "rule 0:zero: divisor should be at least 1")
+	td.CmpTrue(t, errors.Is(err, fizzbuzz.ErrInvalidRule))
+
+	_, err = fizzbuzz.NewRules(classicRules, fizzbuzz.Combination{Mode: "sum"}, numbers)
+	td.CmpString(t, err, `unknown combination "sum"`)
+	td.CmpTrue(t, errors.Is(err, fizzbuzz.ErrInvalidCombination))
+
+	_, err = fizzbuzz.ParseNumbers("base:99", "")
+	td.CmpString(t, err, `numbers "base:99" should be formatted as base:N, N being from 2 to 36`)
+	td.CmpTrue(t, errors.Is(err, fizzbuzz.ErrInvalidNumbers))
+
+	_, err = fizzbuzz.NewGenerator(newRules(t, classicRules, fizzbuzz.Combination{}), big.NewInt(1), big.NewInt(2), 0)
+	td.CmpString(t, err, "step should be at least 1")
+	td.CmpTrue(t, errors.Is(err, fizzbuzz.ErrInvalidRange))
+
+	var r fizzbuzz.Rule
+	err = r.UnmarshalParam("fizz")
+	td.CmpString(t, err, `rule "fizz" should be formatted as divisor:word`)
+	td.CmpTrue(t, errors.Is(err, fizzbuzz.ErrInvalidRule))
+
+	var o fizzbuzz.Override
+	err = o.UnmarshalParam("x:bingo")
+	td.CmpTrue(t, errors.Is(err, fizzbuzz.ErrInvalidCombination))
+	td.CmpTrue(t, errors.Is(err, strconv.ErrSyntax), "wrapped error is kept")
+}
@@ -0,0 +1,156 @@
+package fizzbuzz
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+)
+
+// Generator iterates over the terms of a range, from a starting value up to
+// an inclusive bound, every step values.
+//
+// It uses int arithmetic whenever the whole range fits in an int, and
+// switches to arbitrary precision otherwise. It is not safe for concurrent
+// use, but many generators may share the same Rules.
+type Generator struct {
+	rs         *Rules
+	from, to   *big.Int
+	step       int
+	count, pos uint64
+
+	small         bool
+	v             int
+	bigV, bigStep *big.Int
+}
+
+// NewGenerator starts iterating over the terms of rs from from to to, every
+// step values.
+//
+// Its error matches ErrInvalidRange.
+func NewGenerator(rs *Rules, from, to *big.Int, step int) (*Generator, error) {
+	if from == nil || to == nil {
+		return nil, invalid(ErrInvalidRange, errors.New("range should have a start and an end"))
+	}
+	if step < 1 {
+		return nil, invalid(ErrInvalidRange, errors.New("step should be at least 1"))
+	}
+
+	g := &Generator{
+		rs:   rs,
+		from: new(big.Int).Set(from),
+		to:   new(big.Int).Set(to),
+		step: step,
+	}
+
+	if to.Cmp(from) >= 0 {
+		n := new(big.Int).Sub(to, from)
+		n.Quo(n, big.NewInt(int64(step)))
+		if !n.IsUint64() || n.Uint64() == math.MaxUint64 {
+			g.count = math.MaxUint64
+		} else {
+			g.count = n.Uint64() + 1
+		}
+	}
+
+	g.Seek(0)
+	return g, nil
+}
+
+// Count returns the number of terms of the range, saturating at
+// math.MaxUint64.
+func (g *Generator) Count() uint64 {
+	return g.count
+}
+
+// Len returns the number of terms of the range with arbitrary precision,
+// where Count saturates.
+func (g *Generator) Len() *big.Int {
+	n := new(big.Int)
+	if g.to.Cmp(g.from) >= 0 {
+		n.Sub(g.to, g.from)
+		n.Quo(n, big.NewInt(int64(g.step)))
+		n.Add(n, big.NewInt(1))
+	}
+	return n
+}
+
+// Seek moves to the offset-th term of the range, the next one returned by
+// Next.
+func (g *Generator) Seek(offset uint64) {
+	g.pos = offset
+
+	start := new(big.Int).SetUint64(offset)
+	start.Mul(start, big.NewInt(int64(g.step))).Add(start, g.from)
+
+	if fitsInt(start) && fitsInt(g.to) {
+		g.small, g.v = true, int(start.Int64())
+		g.bigV, g.bigStep = nil, nil
+	} else {
+		g.small, g.bigV, g.bigStep = false, start, big.NewInt(int64(g.step))
+	}
+}
+
+// Value returns the value of the term returned by the next Next call.
+func (g *Generator) Value() *big.Int {
+	if g.small {
+		return big.NewInt(int64(g.v))
+	}
+	return new(big.Int).Set(g.bigV)
+}
+
+// Next returns the next term, false once the range is over.
+func (g *Generator) Next() (string, bool) {
+	if g.pos >= g.count {
+		return "", false
+	}
+	g.pos++
+
+	if g.small {
+		term := g.rs.term(g.v)
+		// may overflow past the range's end, the value is then never used
+		g.v += g.step
+		return term, true
+	}
+
+	term := g.rs.Term(g.bigV)
+	g.bigV.Add(g.bigV, g.bigStep)
+	return term, true
+}
+
+// Term computes the term of v, whether it belongs to the range or not.
+func (g *Generator) Term(v *big.Int) string {
+	return g.rs.Term(v)
+}
+
+// WriteTo writes the remaining terms to w, one per line.
+//
+// It implements io.WriterTo.
+func (g *Generator) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	for term, ok := g.Next(); ok; term, ok = g.Next() {
+		if _, err := bw.WriteString(term); err != nil {
+			return cw.n, err
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return cw.n, err
+		}
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(b []byte) (int, error) {
+	n, err := cw.w.Write(b)
+	cw.n += int64(n)
+	return n, err
+}
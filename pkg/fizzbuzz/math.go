@@ -0,0 +1,47 @@
+package fizzbuzz
+
+import (
+	"math"
+	"math/big"
+)
+
+// GCD computes the Greatest Common Divisor (GCD) of a and b via Euclidean
+// algorithm.
+func GCD(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// lcm computes the Least Common Multiple (LCM) of all positive values via
+// GCD.
+//
+// It returns false if the LCM overflows int, see bigLCM.
+func lcm(values ...int) (int, bool) {
+	res := 1
+	for _, v := range values {
+		m := v / GCD(res, v)
+		if res > math.MaxInt/m {
+			return 0, false
+		}
+		res *= m
+	}
+	return res, true
+}
+
+// bigLCM computes the Least Common Multiple (LCM) of all positive values
+// via GCD, with arbitrary precision.
+func bigLCM(values ...*big.Int) *big.Int {
+	res, g := big.NewInt(1), new(big.Int)
+	for _, v := range values {
+		g.GCD(nil, nil, res, v)
+		res.Mul(res, new(big.Int).Quo(v, g))
+	}
+	return res
+}
+
+// fitsInt tells whether v can be converted to an int without overflow.
+func fitsInt(v *big.Int) bool {
+	return v.IsInt64() && v.Int64() >= math.MinInt && v.Int64() <= math.MaxInt
+}
@@ -0,0 +1,393 @@
+package fizzbuzz
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Number rendering modes, telling how terms matching no rule are written.
+const (
+	NumbersDecimal = "decimal"
+	NumbersHex     = "hex"
+	NumbersBinary  = "binary"
+	NumbersBase    = "base"
+	NumbersRoman   = "roman"
+	NumbersPadded  = "padded"
+	NumbersWords   = "words"
+)
+
+// MaxPaddedWidth is the maximum width of zero-padded numbers.
+const MaxPaddedWidth = 64
+
+// Numbers renders the terms matching no rule.
+//
+// Its zero value renders decimal numbers.
+type Numbers struct {
+	mode string
+	// base is the base of NumbersBase numbers.
+	base int
+	// width is the minimum number of digits of NumbersPadded numbers.
+	width int
+	// lang is the language NumbersWords numbers are spelled out in.
+	lang string
+}
+
+// ParseNumbers parses a numbers parameter, one of decimal, hex, binary,
+// base:N with N from 2 to 36, roman, padded:N or words.
+//
+// acceptLanguage is the Accept-Language header spelled-out numbers pick
+// their language from, English being the default one.
+//
+// Its error matches ErrInvalidNumbers.
+func ParseNumbers(param, acceptLanguage string) (Numbers, error) {
+	mode, arg, hasArg := strings.Cut(param, ":")
+
+	var nf Numbers
+	switch mode {
+	case "", NumbersDecimal:
+		nf.mode = NumbersDecimal
+	case NumbersRoman:
+		nf.mode = mode
+	case NumbersHex:
+		nf.mode, nf.base = NumbersBase, 16
+	case NumbersBinary:
+		nf.mode, nf.base = NumbersBase, 2
+	case NumbersBase:
+		base, err := strconv.Atoi(arg)
+		if err != nil || base < 2 || base > 36 {
+			return nf, invalid(ErrInvalidNumbers, fmt.Errorf("numbers %q should be formatted as base:N, N being from 2 to 36", param))
+		}
+		nf.mode, nf.base = mode, base
+	case NumbersPadded:
+		width, err := strconv.Atoi(arg)
+		if err != nil || width < 1 || width > MaxPaddedWidth {
+			return nf, invalid(ErrInvalidNumbers, fmt.Errorf("numbers %q should be formatted as padded:N, N being from 1 to %d", param, MaxPaddedWidth))
+		}
+		nf.mode, nf.width = mode, width
+	case NumbersWords:
+		nf.mode, nf.lang = mode, spellingLanguage(acceptLanguage)
+	default:
+		return nf, invalid(ErrInvalidNumbers, fmt.Errorf(
+			"numbers %q should be one of decimal, hex, binary, base:N, roman, padded:N or words",
+			param,
+		))
+	}
+
+	if hasArg && nf.mode != NumbersBase && nf.mode != NumbersPadded {
+		return Numbers{}, invalid(ErrInvalidNumbers, fmt.Errorf("numbers %q does not take an argument", mode))
+	}
+	return nf, nil
+}
+
+// Language returns the language words numbers are spelled out in, empty
+// for other modes.
+func (nf Numbers) Language() string {
+	return nf.lang
+}
+
+// format renders v.
+func (nf Numbers) format(v int) string {
+	switch nf.mode {
+	case NumbersBase:
+		return strconv.FormatInt(int64(v), nf.base)
+	case NumbersRoman:
+		if s, ok := roman(v); ok {
+			return s
+		}
+	case NumbersPadded, NumbersWords:
+		return nf.formatBig(big.NewInt(int64(v)))
+	}
+	// strconv is more efficient than fmt.Sprint
+	return strconv.Itoa(v)
+}
+
+// formatBig renders v with arbitrary precision.
+func (nf Numbers) formatBig(v *big.Int) string {
+	switch nf.mode {
+	case NumbersBase:
+		return v.Text(nf.base)
+	case NumbersPadded:
+		digits := new(big.Int).Abs(v).String()
+		if len(digits) < nf.width {
+			digits = strings.Repeat("0", nf.width-len(digits)) + digits
+		}
+		if v.Sign() < 0 {
+			return "-" + digits
+		}
+		return digits
+	case NumbersWords:
+		if s, ok := spellers[nf.lang](v); ok {
+			return s
+		}
+	}
+	// roman numerals do not go beyond 3999
+	return v.String()
+}
+
+// roman writes v in roman numerals, from 1 to 3999.
+func roman(v int) (string, bool) {
+	if v < 1 || v > 3999 {
+		return "", false
+	}
+
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+
+	var sb strings.Builder
+	for i, value := range values {
+		for v >= value {
+			sb.WriteString(symbols[i])
+			v -= value
+		}
+	}
+	return sb.String(), true
+}
+
+// spellers spell numbers out, by language. They return false for numbers
+// beyond their largest scale word.
+var spellers = map[string]func(v *big.Int) (string, bool){
+	"en": spellEnglish,
+	"fr": spellFrench,
+}
+
+// spellingLanguage picks the spellers language from an Accept-Language
+// header, English being the default one.
+func spellingLanguage(acceptLanguage string) string {
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if _, ok := spellers[primary]; ok {
+			return primary
+		}
+	}
+	return "en"
+}
+
+// parseAcceptLanguage returns the acceptable language tags of an
+// Accept-Language header, ordered by decreasing quality.
+func parseAcceptLanguage(acceptLanguage string) []string {
+	type languageRange struct {
+		tag     string
+		quality float64
+	}
+
+	var ranges []languageRange
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		r := languageRange{tag: strings.TrimSpace(tag), quality: 1}
+
+		for _, param := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if k != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(v, 64); err == nil {
+				r.quality = q
+			}
+		}
+
+		if r.tag != "" && r.quality > 0 {
+			ranges = append(ranges, r)
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	tags := make([]string, len(ranges))
+	for i, r := range ranges {
+		tags[i] = r.tag
+	}
+	return tags
+}
+
+// thousands splits the absolute value of v in groups of three digits, the
+// least significant first.
+func thousands(v *big.Int) []int {
+	digits := new(big.Int).Abs(v).String()
+
+	var groups []int
+	for end := len(digits); end > 0; end -= 3 {
+		start := end - 3
+		if start < 0 {
+			start = 0
+		}
+		g, _ := strconv.Atoi(digits[start:end])
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+var (
+	englishUnits = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen",
+		"seventeen", "eighteen", "nineteen",
+	}
+	englishTens = []string{
+		"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety",
+	}
+	englishScales = []string{
+		"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion",
+		"sextillion", "septillion", "octillion", "nonillion", "decillion",
+	}
+)
+
+// spellEnglish spells v out in English, e.g. one hundred twenty-three.
+func spellEnglish(v *big.Int) (string, bool) {
+	groups := thousands(v)
+	if len(groups) > len(englishScales) {
+		return "", false
+	}
+	if v.Sign() == 0 {
+		return englishUnits[0], true
+	}
+
+	var words []string
+	for scale := len(groups) - 1; scale >= 0; scale-- {
+		if groups[scale] == 0 {
+			continue
+		}
+		words = append(words, englishUnder1000(groups[scale]))
+		if scale > 0 {
+			words = append(words, englishScales[scale])
+		}
+	}
+
+	if v.Sign() < 0 {
+		words = append([]string{"minus"}, words...)
+	}
+	return strings.Join(words, " "), true
+}
+
+func englishUnder1000(n int) string {
+	var words []string
+	if n >= 100 {
+		words = append(words, englishUnits[n/100], "hundred")
+		n %= 100
+		if n == 0 {
+			return strings.Join(words, " ")
+		}
+	}
+
+	switch {
+	case n < 20:
+		words = append(words, englishUnits[n])
+	case n%10 == 0:
+		words = append(words, englishTens[n/10])
+	default:
+		words = append(words, englishTens[n/10]+"-"+englishUnits[n%10])
+	}
+	return strings.Join(words, " ")
+}
+
+var (
+	frenchUnits = []string{
+		"zéro", "un", "deux", "trois", "quatre", "cinq", "six", "sept", "huit", "neuf",
+		"dix", "onze", "douze", "treize", "quatorze", "quinze", "seize",
+	}
+	frenchTens = []string{
+		"", "dix", "vingt", "trente", "quarante", "cinquante", "soixante",
+	}
+	// frenchScales follow the long scale, mille being invariable.
+	frenchScales = []string{
+		"", "mille", "million", "milliard", "billion", "billiard", "trillion",
+		"trilliard", "quadrillion", "quadrilliard", "quintillion", "quintilliard",
+	}
+)
+
+// spellFrench spells v out in French, e.g. cent vingt-trois, following the
+// traditional hyphenation.
+func spellFrench(v *big.Int) (string, bool) {
+	groups := thousands(v)
+	if len(groups) > len(frenchScales) {
+		return "", false
+	}
+	if v.Sign() == 0 {
+		return frenchUnits[0], true
+	}
+
+	var words []string
+	for scale := len(groups) - 1; scale >= 0; scale-- {
+		g := groups[scale]
+		switch {
+		case g == 0:
+			continue
+		case scale == 0:
+			words = append(words, frenchUnder1000(g, true))
+		case scale == 1:
+			// mille is invariable, never preceded by un, and
+			// vingt/cent lose their plural mark before it
+			if g > 1 {
+				words = append(words, frenchUnder1000(g, false))
+			}
+			words = append(words, frenchScales[scale])
+		default:
+			name := frenchScales[scale]
+			if g > 1 {
+				name += "s"
+			}
+			words = append(words, frenchUnder1000(g, true), name)
+		}
+	}
+
+	if v.Sign() < 0 {
+		words = append([]string{"moins"}, words...)
+	}
+	return strings.Join(words, " "), true
+}
+
+// frenchUnder1000 spells n out, plural tells whether quatre-vingts and
+// cents keep their plural mark.
+func frenchUnder1000(n int, plural bool) string {
+	hundreds, rest := n/100, n%100
+
+	var prefix string
+	switch {
+	case hundreds == 0:
+		return frenchUnder100(rest, plural)
+	case hundreds == 1:
+		prefix = "cent"
+	case rest == 0 && plural:
+		return frenchUnits[hundreds] + " cents"
+	default:
+		prefix = frenchUnits[hundreds] + " cent"
+	}
+
+	if rest == 0 {
+		return prefix
+	}
+	return prefix + " " + frenchUnder100(rest, plural)
+}
+
+func frenchUnder100(n int, plural bool) string {
+	switch {
+	case n <= 16:
+		return frenchUnits[n]
+	case n < 20:
+		return "dix-" + frenchUnits[n-10]
+	case n < 70:
+		tens, units := frenchTens[n/10], n%10
+		switch units {
+		case 0:
+			return tens
+		case 1:
+			return tens + " et un"
+		default:
+			return tens + "-" + frenchUnits[units]
+		}
+	case n == 71:
+		return "soixante et onze"
+	case n < 80:
+		return "soixante-" + frenchUnder100(n-60, plural)
+	case n == 80 && plural:
+		return "quatre-vingts"
+	case n == 80:
+		return "quatre-vingt"
+	default:
+		return "quatre-vingt-" + frenchUnder100(n-80, plural)
+	}
+}
@@ -0,0 +1,212 @@
+package fizzbuzz
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Built-in predicate kinds.
+const (
+	KindDivisible     = "divisible"
+	KindContainsDigit = "contains_digit"
+	KindIsPrime       = "is_prime"
+	KindIsSquare      = "is_square"
+	KindInRange       = "in_range"
+	KindEndsWith      = "ends_with"
+)
+
+// Predicate tells whether a rule's word replaces a number.
+//
+// MatchBig is only called for numbers which do not fit in an int.
+type Predicate interface {
+	Match(v int) bool
+	MatchBig(v *big.Int) bool
+}
+
+// PredicateKind describes how to build a Predicate from a rule argument.
+type PredicateKind struct {
+	// NoArg is set for predicates without argument, such as is_prime.
+	NoArg bool
+	// New builds the predicate from the rule's argument.
+	New func(arg string) (Predicate, error)
+}
+
+var predicateKinds = map[string]PredicateKind{
+	KindDivisible:     {New: newDivisible},
+	KindContainsDigit: {New: newContainsDigit},
+	KindIsPrime:       {NoArg: true, New: func(string) (Predicate, error) { return isPrime{}, nil }},
+	KindIsSquare:      {NoArg: true, New: func(string) (Predicate, error) { return isSquare{}, nil }},
+	KindInRange:       {New: newInRange},
+	KindEndsWith:      {New: newEndsWith},
+}
+
+// RegisterPredicateKind makes a new kind of predicate available to rules.
+//
+// It is not safe for concurrent use and should be called before any
+// Rules is built, typically from an init function.
+func RegisterPredicateKind(name string, kind PredicateKind) {
+	predicateKinds[name] = kind
+}
+
+// divisible matches multiples of a divisor.
+type divisible int
+
+// bigDivisible matches multiples of a divisor which does not fit in an int.
+type bigDivisible struct {
+	d *big.Int
+}
+
+func newDivisible(arg string) (Predicate, error) {
+	d, ok := new(big.Int).SetString(arg, 10)
+	if !ok {
+		return nil, fmt.Errorf("%q is not an integer", arg)
+	}
+	if d.Sign() < 1 {
+		return nil, errors.New("divisor should be at least 1")
+	}
+
+	if fitsInt(d) {
+		return divisible(d.Int64()), nil
+	}
+	return bigDivisible{d: d}, nil
+}
+
+func (d divisible) Match(v int) bool {
+	return v%int(d) == 0
+}
+
+func (d divisible) MatchBig(v *big.Int) bool {
+	return new(big.Int).Rem(v, big.NewInt(int64(d))).Sign() == 0
+}
+
+func (d bigDivisible) Match(v int) bool {
+	// |v| is lower than d
+	return v == 0
+}
+
+func (d bigDivisible) MatchBig(v *big.Int) bool {
+	return new(big.Int).Rem(v, d.d).Sign() == 0
+}
+
+// containsDigit matches numbers written with a given decimal digit.
+type containsDigit byte
+
+func newContainsDigit(arg string) (Predicate, error) {
+	if len(arg) != 1 || arg[0] < '0' || arg[0] > '9' {
+		return nil, fmt.Errorf("%q is not a digit", arg)
+	}
+	return containsDigit(arg[0]), nil
+}
+
+func (d containsDigit) Match(v int) bool {
+	return strings.IndexByte(strconv.Itoa(v), byte(d)) >= 0
+}
+
+func (d containsDigit) MatchBig(v *big.Int) bool {
+	return strings.IndexByte(v.String(), byte(d)) >= 0
+}
+
+// isPrime matches prime numbers.
+type isPrime struct{}
+
+func (isPrime) Match(v int) bool {
+	// ProbablyPrime is 100% accurate for values lower than 2^64
+	return v > 1 && big.NewInt(int64(v)).ProbablyPrime(0)
+}
+
+func (isPrime) MatchBig(v *big.Int) bool {
+	// beyond 2^64, the error probability is at most 4^-20
+	return v.Sign() > 0 && v.ProbablyPrime(20)
+}
+
+// isSquare matches perfect squares.
+type isSquare struct{}
+
+func (isSquare) Match(v int) bool {
+	if v < 0 {
+		return false
+	}
+
+	// float rounding may be off by one for large values,
+	// divisions avoid overflowing while fixing it
+	r := int(math.Sqrt(float64(v)))
+	for r > 0 && r > v/r {
+		r--
+	}
+	for r+1 <= v/(r+1) {
+		r++
+	}
+	return r*r == v
+}
+
+func (isSquare) MatchBig(v *big.Int) bool {
+	if v.Sign() < 0 {
+		return false
+	}
+
+	r := new(big.Int).Sqrt(v)
+	return r.Mul(r, r).Cmp(v) == 0
+}
+
+// inRange matches numbers between two inclusive bounds.
+type inRange struct {
+	min, max int
+}
+
+func newInRange(arg string) (Predicate, error) {
+	lo, hi, found := strings.Cut(arg, "..")
+	if !found {
+		return nil, fmt.Errorf("range %q should be formatted as min..max", arg)
+	}
+
+	var (
+		r   inRange
+		err error
+	)
+	if r.min, err = strconv.Atoi(lo); err != nil {
+		return nil, err
+	}
+	if r.max, err = strconv.Atoi(hi); err != nil {
+		return nil, err
+	}
+	if r.min > r.max {
+		return nil, fmt.Errorf("range %q is empty", arg)
+	}
+	return r, nil
+}
+
+func (r inRange) Match(v int) bool {
+	return r.min <= v && v <= r.max
+}
+
+func (r inRange) MatchBig(*big.Int) bool {
+	// bounds fit in an int
+	return false
+}
+
+// endsWith matches numbers whose decimal notation ends with given digits.
+type endsWith string
+
+func newEndsWith(arg string) (Predicate, error) {
+	if arg == "" {
+		return nil, errors.New("suffix should not be empty")
+	}
+	for _, c := range arg {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("%q is not a number", arg)
+		}
+	}
+	return endsWith(arg), nil
+}
+
+func (s endsWith) Match(v int) bool {
+	return strings.HasSuffix(strconv.Itoa(v), string(s))
+}
+
+func (s endsWith) MatchBig(v *big.Int) bool {
+	return strings.HasSuffix(v.String(), string(s))
+}
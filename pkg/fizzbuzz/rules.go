@@ -0,0 +1,303 @@
+package fizzbuzz
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Rule associates a predicate to the word replacing the numbers it matches.
+//
+// Kind selects the predicate among the registered PredicateKind, Arg being
+// its argument. Divisible rules, the default kind, may provide their
+// argument through Divisor instead.
+//
+// On query parameters, a rule is written as `kind:arg:word`, e.g.
+// `contains_digit:3:fizz`, or `kind:word` for kinds without argument,
+// e.g. `is_prime:fizz`. Divisible rules may be written as `divisor:word`,
+// e.g. `7:woof`.
+type Rule struct {
+	Kind    string `json:"kind,omitempty"`
+	Arg     string `json:"arg,omitempty"`
+	Divisor int    `json:"divisor,omitempty"`
+	Word    string `json:"word"`
+}
+
+// UnmarshalParam parses a `kind:arg:word` or `divisor:word` query parameter.
+//
+// It implements echo.BindUnmarshaler.
+func (r *Rule) UnmarshalParam(param string) error {
+	head, tail, found := strings.Cut(param, ":")
+	if !found {
+		return invalid(ErrInvalidRule, fmt.Errorf("rule %q should be formatted as divisor:word", param))
+	}
+
+	if kind, ok := predicateKinds[head]; ok {
+		r.Kind = head
+		if kind.NoArg {
+			r.Word = tail
+			return nil
+		}
+
+		arg, word, found := strings.Cut(tail, ":")
+		if !found {
+			return invalid(ErrInvalidRule, fmt.Errorf("rule %q should be formatted as %s:arg:word", param, head))
+		}
+		r.Arg, r.Word = arg, word
+		return nil
+	}
+
+	d, err := strconv.Atoi(head)
+	if errors.Is(err, strconv.ErrRange) {
+		// divisors beyond int are kept as divisible rules argument
+		if _, ok := new(big.Int).SetString(head, 10); ok {
+			r.Arg, r.Word = head, tail
+			return nil
+		}
+	}
+	if err != nil {
+		return invalid(ErrInvalidRule, fmt.Errorf("rule %q has an invalid divisor: %w", param, err))
+	}
+
+	r.Divisor, r.Word = d, tail
+	return nil
+}
+
+// String formats the rule the same way it is read from query parameters.
+func (r Rule) String() string {
+	switch kind := r.KindName(); {
+	case kind == KindDivisible && r.Arg == "":
+		return strconv.Itoa(r.Divisor) + ":" + r.Word
+	case kind == KindDivisible:
+		return r.Arg + ":" + r.Word
+	case predicateKinds[kind].NoArg:
+		return kind + ":" + r.Word
+	default:
+		return kind + ":" + r.Arg + ":" + r.Word
+	}
+}
+
+// KindName returns the rule's kind, KindDivisible when not set.
+func (r Rule) KindName() string {
+	if r.Kind == "" {
+		return KindDivisible
+	}
+	return r.Kind
+}
+
+// Predicate builds the rule's predicate from its kind and argument.
+//
+// Its error matches ErrUnknownKind or ErrInvalidRule.
+func (r Rule) Predicate() (Predicate, error) {
+	name := r.KindName()
+	kind, ok := predicateKinds[name]
+	if !ok {
+		return nil, invalid(ErrUnknownKind, fmt.Errorf("unknown rule kind %q", name))
+	}
+
+	arg := r.Arg
+	if name == KindDivisible && arg == "" {
+		arg = strconv.Itoa(r.Divisor)
+	}
+
+	p, err := kind.New(arg)
+	if err != nil {
+		return nil, invalid(ErrInvalidRule, err)
+	}
+	return p, nil
+}
+
+// Rules is an ordered list of rules ready to compute fizzbuzz terms.
+//
+// It is safe for concurrent use.
+type Rules struct {
+	rules      []Rule
+	predicates []Predicate
+	comb       Combination
+	numbers    Numbers
+
+	// divisors are the rules divisors when all rules are divisible ones,
+	// nil otherwise.
+	divisors []*big.Int
+	// bigPeriod is the lcm of divisors: its multiples match every rule.
+	// It is nil when divisors are, or when there is no rule.
+	bigPeriod *big.Int
+	// period is bigPeriod when it fits in an int, 0 otherwise.
+	period int
+	// all is the combination of every rule's word, replacing bigPeriod
+	// multiples unless overrides apply.
+	all string
+}
+
+// NewRules checks the rules and their combination, numbers rendering the
+// terms matching no rule.
+//
+// Its error matches ErrUnknownKind, ErrInvalidRule or
+// ErrInvalidCombination.
+func NewRules(rules []Rule, comb Combination, numbers Numbers) (*Rules, error) {
+	if err := comb.validate(); err != nil {
+		return nil, err
+	}
+
+	rs := &Rules{
+		rules:      rules,
+		predicates: make([]Predicate, len(rules)),
+		comb:       comb,
+		numbers:    numbers,
+	}
+
+	divisors := make([]*big.Int, 0, len(rules))
+	words := make([]string, len(rules))
+	for i, r := range rules {
+		p, err := r.Predicate()
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r, err)
+		}
+
+		rs.predicates[i], words[i] = p, r.Word
+		switch d := p.(type) {
+		case divisible:
+			divisors = append(divisors, big.NewInt(int64(d)))
+		case bigDivisible:
+			divisors = append(divisors, d.d)
+		}
+	}
+
+	if len(divisors) == len(rules) {
+		rs.divisors = divisors
+	}
+	// without any rule, no number matches every rule
+	if len(rs.divisors) > 0 {
+		rs.bigPeriod = divisorsLCM(divisors)
+		if fitsInt(rs.bigPeriod) {
+			rs.period = int(rs.bigPeriod.Int64())
+		}
+	}
+	if len(words) > 0 {
+		rs.all = comb.Combine(words)
+	}
+	return rs, nil
+}
+
+// Rules returns the ordered list of rules.
+func (rs *Rules) Rules() []Rule {
+	return rs.rules
+}
+
+// Combination returns how the words of numbers matching several rules are
+// combined.
+func (rs *Rules) Combination() Combination {
+	return rs.comb
+}
+
+// Numbers returns how the numbers matching no rule are rendered.
+func (rs *Rules) Numbers() Numbers {
+	return rs.numbers
+}
+
+// Divisors returns the divisor of every rule when all rules are divisible
+// ones, nil otherwise.
+func (rs *Rules) Divisors() []*big.Int {
+	return rs.divisors
+}
+
+// Period returns the lcm of Divisors, whose multiples match every rule, or
+// nil when not all rules are divisible ones or when there is no rule.
+//
+// Overrides are left out.
+func (rs *Rules) Period() *big.Int {
+	if rs.bigPeriod == nil {
+		return nil
+	}
+	return new(big.Int).Set(rs.bigPeriod)
+}
+
+// Term computes the fizzbuzz value of v.
+//
+// Words of every matching rule are combined according to the rules
+// combination, by default concatenated in rule order. When no rule
+// matches, v itself is returned, written according to the rules number
+// rendering.
+func (rs *Rules) Term(v *big.Int) string {
+	if fitsInt(v) {
+		return rs.term(int(v.Int64()))
+	}
+
+	if rs.bigPeriod != nil && len(rs.comb.Overrides) == 0 &&
+		new(big.Int).Rem(v, rs.bigPeriod).Sign() == 0 {
+		return rs.all
+	}
+
+	var words []string
+	for i, p := range rs.predicates {
+		if p.MatchBig(v) {
+			words = append(words, rs.rules[i].Word)
+		}
+	}
+
+	switch {
+	case len(words) == 0:
+		return rs.numbers.formatBig(v)
+	case len(words) > 1:
+		if word, ok := rs.comb.overrideBig(v); ok {
+			return word
+		}
+	}
+	return rs.comb.Combine(words)
+}
+
+// term computes the fizzbuzz value of v, with int arithmetic.
+func (rs *Rules) term(v int) string {
+	if rs.period != 0 && v%rs.period == 0 && len(rs.comb.Overrides) == 0 {
+		return rs.all
+	}
+
+	var words []string
+	for i, p := range rs.predicates {
+		if p.Match(v) {
+			words = append(words, rs.rules[i].Word)
+		}
+	}
+
+	switch {
+	case len(words) == 0:
+		return rs.numbers.format(v)
+	case len(words) > 1:
+		if word, ok := rs.comb.override(v); ok {
+			return word
+		}
+	}
+	return rs.comb.Combine(words)
+}
+
+// Matching returns the indexes of the rules matching v, in rule order.
+func (rs *Rules) Matching(v *big.Int) []int {
+	small := fitsInt(v)
+
+	indexes := make([]int, 0, len(rs.predicates))
+	for i, p := range rs.predicates {
+		if small && p.Match(int(v.Int64())) || !small && p.MatchBig(v) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// divisorsLCM computes the lcm of divisors, switching to arbitrary
+// precision when they, or their lcm, do not fit in an int.
+func divisorsLCM(divisors []*big.Int) *big.Int {
+	values := make([]int, len(divisors))
+	for i, d := range divisors {
+		if !fitsInt(d) {
+			return bigLCM(divisors...)
+		}
+		values[i] = int(d.Int64())
+	}
+
+	if res, ok := lcm(values...); ok {
+		return big.NewInt(int64(res))
+	}
+	return bigLCM(divisors...)
+}
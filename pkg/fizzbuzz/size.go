@@ -0,0 +1,116 @@
+package fizzbuzz
+
+import (
+	"math"
+	"math/big"
+	"strings"
+)
+
+// maxRomanLen is the length of the longest roman numeral, 3888.
+const maxRomanLen = len("MMMDCCCLXXXVIII")
+
+// Size estimates the total length of the n terms following the generator's
+// position, without computing them. It is an upper bound, saturating at
+// math.MaxUint64, except for spelled-out numbers whose length is
+// approximated.
+//
+// wordLen measures words and separators, e.g. once encoded, nil measuring
+// their length in bytes.
+func (g *Generator) Size(n uint64, wordLen func(string) int) uint64 {
+	if remaining := g.count - g.pos; n > remaining {
+		n = remaining
+	}
+	if n == 0 {
+		return 0
+	}
+	if wordLen == nil {
+		wordLen = func(word string) int { return len(word) }
+	}
+
+	step := big.NewInt(int64(g.step))
+	first := g.Value()
+	last := new(big.Int).SetUint64(n - 1)
+	last.Mul(last, step).Add(last, first)
+
+	// every term is either a number or words
+	bigN := new(big.Int).SetUint64(n)
+	size := new(big.Int).Mul(bigN, big.NewInt(int64(g.rs.numbers.maxLen(first, last))))
+
+	sepLen := big.NewInt(int64(wordLen(g.rs.comb.Separator)))
+	for i, p := range g.rs.predicates {
+		matches := matchCount(p, n, step)
+		wordSize := big.NewInt(int64(wordLen(g.rs.rules[i].Word)))
+		size.Add(size, wordSize.Add(wordSize, sepLen).Mul(wordSize, matches))
+	}
+	for _, o := range g.rs.comb.Overrides {
+		matches := matchCount(divisible(o.Divisor), n, step)
+		wordSize := big.NewInt(int64(wordLen(o.Word)))
+		size.Add(size, wordSize.Mul(wordSize, matches))
+	}
+
+	if !size.IsUint64() {
+		return math.MaxUint64
+	}
+	return size.Uint64()
+}
+
+// matchCount returns an upper bound of the number of values matching p
+// among n values step apart: all of them, unless p is a divisible one.
+func matchCount(p Predicate, n uint64, step *big.Int) *big.Int {
+	var d *big.Int
+	switch p := p.(type) {
+	case divisible:
+		d = big.NewInt(int64(p))
+	case bigDivisible:
+		d = new(big.Int).Set(p.d)
+	default:
+		return new(big.Int).SetUint64(n)
+	}
+
+	// values step apart hit a multiple of d every d/gcd(d, step) values
+	d.Quo(d, new(big.Int).GCD(nil, nil, d, step))
+
+	bigN := new(big.Int).SetUint64(n)
+	matches := new(big.Int).Quo(bigN, d)
+	matches.Add(matches, big.NewInt(1))
+	if matches.Cmp(bigN) > 0 {
+		return bigN
+	}
+	return matches
+}
+
+// maxLen estimates the length of the longest number rendering between lo
+// and hi.
+func (nf Numbers) maxLen(lo, hi *big.Int) int {
+	n := 0
+	for _, v := range []*big.Int{lo, hi} {
+		switch nf.mode {
+		case NumbersRoman:
+			n = maxInt(n, maxRomanLen, len(v.String()))
+		case NumbersWords:
+			// repdigits of 7, 8 and 9 make long words, in every language
+			digits := len(new(big.Int).Abs(v).String())
+			for _, d := range "789" {
+				rep, _ := new(big.Int).SetString(strings.Repeat(string(d), digits), 10)
+				if v.Sign() < 0 {
+					rep.Neg(rep)
+				}
+				n = maxInt(n, len(nf.formatBig(rep)))
+			}
+		default:
+			n = maxInt(n, len(nf.formatBig(v)))
+		}
+	}
+	return n
+}
+
+// maxInt returns the maximum of values.
+func maxInt(values ...int) int {
+	res := values[0]
+	for _, v := range values[1:] {
+		if v > res {
+			res = v
+		}
+	}
+	return res
+}
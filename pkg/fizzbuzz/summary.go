@@ -0,0 +1,213 @@
+package fizzbuzz
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+)
+
+// Summary counts the terms of a range for every combination of matching
+// rules, see Generator.Summary.
+type Summary struct {
+	// Count is the number of terms of the range.
+	Count *big.Int
+	// Words holds one entry per combination of matching rules, the i-th
+	// one counting the terms matching exactly the rules of bitmask i+1.
+	Words []SummaryCount
+	// Numbers counts the terms matching no rule.
+	Numbers SummaryCount
+}
+
+// SummaryCount counts the terms of a range replaced by a given word.
+type SummaryCount struct {
+	// Rules are the indexes of the matching rules, in rule order.
+	Rules []int
+	// Word combines the words of the matching rules, empty for numbers.
+	Word  string
+	Count *big.Int
+	// First is the first value of the range replaced by Word, nil when
+	// Count is 0.
+	First *big.Int
+	// Density is Count's ratio among all terms of the range.
+	Density float64
+}
+
+// Period returns the number of terms after which the terms of the range
+// repeat themselves, or nil when not all rules are divisible ones.
+//
+// Unlike Rules.Period, it accounts for overrides and for the range's step.
+// It may exceed the range's length.
+func (g *Generator) Period() *big.Int {
+	period := g.rs.Period()
+	if period == nil {
+		return nil
+	}
+
+	// overrides are periodic too
+	for _, o := range g.rs.comb.Overrides {
+		period = bigLCM(period, big.NewInt(int64(o.Divisor)))
+	}
+
+	// the period counts values, terms are step values apart
+	step := big.NewInt(int64(g.step))
+	return period.Quo(period, new(big.Int).GCD(nil, nil, period, step))
+}
+
+// Summary counts the terms of the whole range for every combination of
+// matching rules, and the numbers matching none, without computing any
+// term.
+//
+// Counts rely on the inclusion–exclusion principle over the divisors lcm,
+// hence any range size is summarized instantly, but 2^n combinations of n
+// rules are counted. Only divisible rules are supported, without
+// overrides: its error matches ErrInvalidRule or ErrInvalidCombination
+// otherwise.
+func (g *Generator) Summary() (Summary, error) {
+	divisors := g.rs.Divisors()
+	if divisors == nil {
+		return Summary{}, invalid(ErrInvalidRule, errors.New("summary only supports divisible rules"))
+	}
+	if len(g.rs.comb.Overrides) > 0 {
+		return Summary{}, invalid(ErrInvalidCombination, errors.New("summary does not support override"))
+	}
+
+	r := arithmeticRange{from: g.from, step: big.NewInt(int64(g.step)), count: g.Len()}
+
+	// atLeast[mask] counts the values matching at least mask's rules: the
+	// multiples of their lcm
+	subsets := 1 << len(divisors)
+	lcms := make([]*big.Int, subsets)
+	atLeast := make([]*big.Int, subsets)
+	for mask := 0; mask < subsets; mask++ {
+		var subset []*big.Int
+		for i, d := range divisors {
+			if mask&(1<<i) != 0 {
+				subset = append(subset, d)
+			}
+		}
+
+		lcms[mask] = bigLCM(subset...)
+		atLeast[mask], _ = r.multiples(lcms[mask])
+	}
+
+	// inclusion–exclusion turns them into values matching exactly mask's
+	// rules
+	exactly := make([]*big.Int, subsets)
+	for mask := range exactly {
+		exactly[mask] = new(big.Int)
+		for superset := mask; superset < subsets; superset = (superset + 1) | mask {
+			if bits.OnesCount(uint(superset^mask))%2 == 0 {
+				exactly[mask].Add(exactly[mask], atLeast[superset])
+			} else {
+				exactly[mask].Sub(exactly[mask], atLeast[superset])
+			}
+		}
+	}
+
+	s := Summary{
+		Count:   r.count,
+		Words:   make([]SummaryCount, 0, subsets-1),
+		Numbers: r.summaryCount(exactly[0], r.firstExactly(lcms[0], divisors, 0, exactly[0])),
+	}
+	for mask := 1; mask < subsets; mask++ {
+		count := r.summaryCount(exactly[mask], r.firstExactly(lcms[mask], divisors, mask, exactly[mask]))
+
+		var words []string
+		for i := range divisors {
+			if mask&(1<<i) != 0 {
+				words = append(words, g.rs.rules[i].Word)
+				count.Rules = append(count.Rules, i)
+			}
+		}
+		count.Word = g.rs.comb.Combine(words)
+		s.Words = append(s.Words, count)
+	}
+	return s, nil
+}
+
+// arithmeticRange describes count values, from from and separated by step.
+type arithmeticRange struct {
+	from, step, count *big.Int
+}
+
+// multiples counts the range's multiples of m. When there is any, it also
+// returns the index of the first one and the index gap between two
+// consecutive ones.
+func (r arithmeticRange) multiples(m *big.Int) (*big.Int, *arithmeticProgression) {
+	// from + step*k ≡ 0 (mod m) is solvable iff gcd(step, m) divides from
+	g := new(big.Int).GCD(nil, nil, r.step, m)
+	if new(big.Int).Rem(r.from, g).Sign() != 0 {
+		return new(big.Int), nil
+	}
+
+	// then k ≡ -from/g * (step/g)^-1 (mod m/g)
+	gap := new(big.Int).Quo(m, g)
+	k := new(big.Int).Quo(r.from, g)
+	k.Neg(k).Mod(k, gap)
+	if gap.Cmp(big.NewInt(1)) != 0 {
+		inv := new(big.Int).ModInverse(new(big.Int).Quo(r.step, g), gap)
+		k.Mul(k, inv).Mod(k, gap)
+	}
+
+	if k.Cmp(r.count) >= 0 {
+		return new(big.Int), nil
+	}
+
+	count := new(big.Int).Sub(r.count, k)
+	count.Sub(count, big.NewInt(1))
+	count.Quo(count, gap)
+	count.Add(count, big.NewInt(1))
+	return count, &arithmeticProgression{first: k, gap: gap}
+}
+
+// arithmeticProgression describes range indexes, from first and separated
+// by gap.
+type arithmeticProgression struct {
+	first, gap *big.Int
+}
+
+// value returns the range's k-th value.
+func (r arithmeticRange) value(k *big.Int) *big.Int {
+	v := new(big.Int).Mul(r.step, k)
+	return v.Add(v, r.from)
+}
+
+// firstExactly returns the first range value matching exactly mask's
+// divisors, or nil if count, their number, is 0.
+//
+// It walks through the multiples of lcm, mask's divisors lcm, until one is
+// not a multiple of any other divisor.
+func (r arithmeticRange) firstExactly(lcm *big.Int, divisors []*big.Int, mask int, count *big.Int) *big.Int {
+	if count.Sign() == 0 {
+		return nil
+	}
+
+	_, p := r.multiples(lcm)
+	k, rem := new(big.Int).Set(p.first), new(big.Int)
+	for {
+		v := r.value(k)
+
+		matchesOther := false
+		for i, d := range divisors {
+			if mask&(1<<i) == 0 && rem.Rem(v, d).Sign() == 0 {
+				matchesOther = true
+				break
+			}
+		}
+		if !matchesOther {
+			return v
+		}
+
+		k.Add(k, p.gap)
+	}
+}
+
+// summaryCount builds a SummaryCount of count range values, first being
+// the first of them.
+func (r arithmeticRange) summaryCount(count, first *big.Int) SummaryCount {
+	out := SummaryCount{Count: count, First: first}
+	if r.count.Sign() > 0 {
+		out.Density, _ = new(big.Rat).SetFrac(count, r.count).Float64()
+	}
+	return out
+}